@@ -1,20 +1,27 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"io"
 	"log"
 	"os"
+	"os/signal"
 	"path/filepath"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/trodemaster/weatherdesktop/pkg/assets"
 	"github.com/trodemaster/weatherdesktop/pkg/downloader"
+	"github.com/trodemaster/weatherdesktop/pkg/history"
 	pkgimage "github.com/trodemaster/weatherdesktop/pkg/image"
 	"github.com/trodemaster/weatherdesktop/pkg/parser"
 	"github.com/trodemaster/weatherdesktop/pkg/playwright"
+	"github.com/trodemaster/weatherdesktop/pkg/results"
+	"github.com/trodemaster/weatherdesktop/pkg/scrapeserver"
 )
 
 // copyFile copies a file from src to dst
@@ -43,7 +50,8 @@ func main() {
 	if len(os.Args) < 2 {
 		fmt.Fprintf(os.Stderr, "Usage: wd-worker <command> [options]\n")
 		fmt.Fprintf(os.Stderr, "\nCommands:\n")
-		fmt.Fprintf(os.Stderr, "  scrape   Scrape websites\n")
+		fmt.Fprintf(os.Stderr, "  scrape   Scrape websites (via the scraper service if running)\n")
+		fmt.Fprintf(os.Stderr, "  serve    Run the scraper service with a warm browser\n")
 		fmt.Fprintf(os.Stderr, "  download Download images\n")
 		fmt.Fprintf(os.Stderr, "  crop     Crop and resize images\n")
 		fmt.Fprintf(os.Stderr, "  render   Render composite image\n")
@@ -57,6 +65,10 @@ func main() {
 		if err := runScrape(); err != nil {
 			log.Fatalf("Scrape failed: %v", err)
 		}
+	case "serve":
+		if err := runServe(); err != nil {
+			log.Fatalf("Scraper service failed: %v", err)
+		}
 	case "download":
 		if err := runDownload(); err != nil {
 			log.Fatalf("Download failed: %v", err)
@@ -79,6 +91,14 @@ func runScrape() error {
 	scrapeFlags := flag.NewFlagSet("scrape", flag.ExitOnError)
 	debugFlag := scrapeFlags.Bool("debug", false, "Enable debug mode")
 	targetFlag := scrapeFlags.String("target", "", "Filter specific target")
+	parallelFlag := scrapeFlags.Int("parallel", playwright.DefaultParallelism, "Number of targets to scrape concurrently")
+	timeoutFlag := scrapeFlags.Duration("timeout", playwright.DefaultTargetTimeout, "Per-target scrape timeout")
+	evidenceKeepFlag := scrapeFlags.Int("evidence-keep", playwright.DefaultEvidenceKeep, "Failure evidence bundles to keep per target")
+	socketFlag := scrapeFlags.String("socket", scrapeserver.DefaultSocket, "Scraper service socket")
+	localFlag := scrapeFlags.Bool("local", false, "Scrape in this process instead of using the scraper service")
+	harFlag := scrapeFlags.String("har", "", "Record or replay network traffic per target (record|replay, implies --local)")
+	harDirFlag := scrapeFlags.String("har-dir", "", "HAR and golden screenshot directory (default assets/har)")
+	updateGoldenFlag := scrapeFlags.Bool("update-golden", false, "With --har replay, overwrite golden screenshots")
 
 	if err := scrapeFlags.Parse(os.Args[2:]); err != nil {
 		return err
@@ -87,8 +107,44 @@ func runScrape() error {
 	workDir := "/app"
 	mgr := assets.NewManager(workDir)
 
+	harMode := playwright.HARMode(*harFlag)
+	switch harMode {
+	case playwright.HAROff, playwright.HARRecord, playwright.HARReplay:
+	default:
+		return fmt.Errorf("unknown --har mode %q (want record or replay)", *harFlag)
+	}
+	harDir := *harDirFlag
+	if harDir == "" {
+		harDir = mgr.GetHARDir()
+	}
+
+	// Send the run to the warm scraper service when it is running
+	// Record and replay runs always scrape in-process
+	if !*localFlag && harMode == playwright.HAROff {
+		err := scrapeserver.NewClient(*socketFlag).Scrape(scrapeserver.Request{
+			Target:        *targetFlag,
+			Debug:         *debugFlag,
+			Parallelism:   *parallelFlag,
+			TargetTimeout: *timeoutFlag,
+			EvidenceKeep:  *evidenceKeepFlag,
+		}, os.Stderr)
+		if !errors.Is(err, scrapeserver.ErrUnavailable) {
+			return err
+		}
+		log.Printf("Warning: %v, scraping in-process", err)
+	}
+
 	// Create scraper
-	scraper := playwright.New(*debugFlag)
+	scraper := playwright.NewWithOptions(playwright.Options{
+		Debug:         *debugFlag,
+		Parallelism:   *parallelFlag,
+		TargetTimeout: *timeoutFlag,
+		EvidenceDir:   mgr.GetScrapeDebugDir(),
+		EvidenceKeep:  *evidenceKeepFlag,
+		HARMode:       harMode,
+		HARDir:        harDir,
+		UpdateGolden:  *updateGoldenFlag,
+	})
 
 	// Start Playwright
 	if err := scraper.Start(); err != nil {
@@ -96,27 +152,25 @@ func runScrape() error {
 	}
 	defer scraper.Stop()
 
-	log.Println("Scraping sites...")
+	return scraper.Run(mgr, *targetFlag)
+}
 
-	// Scrape targets
-	if *targetFlag != "" {
-		if err := scraper.ScrapeFiltered(mgr, *targetFlag); err != nil {
-			return fmt.Errorf("filtered scrape failed: %w", err)
-		}
-	} else {
-		if err := scraper.ScrapeAll(mgr); err != nil {
-			return fmt.Errorf("scrape failed: %w", err)
-		}
-	}
+func runServe() error {
+	serveFlags := flag.NewFlagSet("serve", flag.ExitOnError)
+	socketFlag := serveFlags.String("socket", scrapeserver.DefaultSocket, "Socket to listen on")
+	maxPagesFlag := serveFlags.Int("max-pages", scrapeserver.DefaultMaxPages, "Restart the browsers after this many pages")
 
-	// Also scrape WSDOT HTML
-	wsdotTarget := mgr.GetWSDOTHTMLTarget()
-	if err := scraper.ScrapeHTML(wsdotTarget); err != nil {
-		log.Printf("Warning: Failed to scrape WSDOT HTML: %v", err)
+	if err := serveFlags.Parse(os.Args[2:]); err != nil {
+		return err
 	}
 
-	log.Println("Asset Collection Completed...")
-	return nil
+	workDir := "/app"
+	mgr := assets.NewManager(workDir)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	return scrapeserver.New(mgr, *maxPagesFlag).ListenAndServe(ctx, *socketFlag)
 }
 
 func runDownload() error {
@@ -131,18 +185,36 @@ func runDownload() error {
 		return fmt.Errorf("download failed: %w", err)
 	}
 
+	// Text products are optional - a failure only leaves the panel out
+	if err := dl.DownloadTextProduct(mgr.GetAFDPanel()); err != nil {
+		log.Printf("Warning: Failed to download forecast discussion: %v", err)
+	}
+
 	log.Println("Downloads completed")
 	return nil
 }
 
 func runCrop() error {
+	cropFlags := flag.NewFlagSet("crop", flag.ExitOnError)
+	debugFlag := cropFlags.Bool("debug", false, "Log per-asset timings")
+	workersFlag := cropFlags.Int("workers", 0, "Assets processed concurrently (0 = GOMAXPROCS)")
+	memoryFlag := cropFlags.Int64("memory-mb", 0, "Memory budget for decoded images in MB (0 = half the container limit)")
+
+	if err := cropFlags.Parse(os.Args[2:]); err != nil {
+		return err
+	}
+
 	workDir := "/app"
 	mgr := assets.NewManager(workDir)
 
 	log.Println("Cropping and resizing images...")
 
 	// Process all crop assets
-	processor := pkgimage.NewProcessor(mgr)
+	processor := pkgimage.NewProcessorWithOptions(mgr, pkgimage.ProcessorOptions{
+		Workers:      *workersFlag,
+		MemoryBudget: *memoryFlag << 20,
+		Debug:        *debugFlag,
+	})
 	if err := processor.ProcessAll(); err != nil {
 		return fmt.Errorf("crop failed: %w", err)
 	}
@@ -161,18 +233,42 @@ func runRender() error {
 
 	log.Printf("Rendering composite image: %s", renderedFilename)
 
-	// Parse WSDOT HTML for pass status and select appropriate graphic
-	wsdotHTML := filepath.Join(mgr.AssetsDir, "wsdot_stevens_pass.html")
+	// Parse WSDOT HTML for all passes; the first (Stevens) drives the status graphic
 	prsr := parser.New()
-	passStatus, err := prsr.ParseWSDOTPassStatus(wsdotHTML)
+	passResults := prsr.ParseWSDOTPasses(mgr.GetPassDefinitions())
+	passStatus, err := passResults[0].Status, passResults[0].Err
 	passConditionsPath := mgr.GetPassConditionsImagePath()
+	tr := pkgimage.NewTextRenderer()
 
-	if err != nil {
+	// Run results record schema drift so it is visible after the run
+	runResults, resultsErr := results.Load(mgr.GetRunResultsPath())
+	if resultsErr != nil {
+		log.Printf("Warning: %v", resultsErr)
+	}
+
+	for _, pr := range passResults {
+		if errors.Is(pr.Err, parser.ErrNoPassData) {
+			// Page parsed but the expected labels are gone - WSDOT likely changed the layout
+			log.Printf("Warning: WSDOT page schema drift detected: %v", pr.Err)
+			runResults.SetWarning(pr.Pass.Source, results.KindSchemaDrift, pr.Err.Error())
+		} else if pr.Err == nil {
+			runResults.ClearWarning(pr.Pass.Source, results.KindSchemaDrift)
+		}
+	}
+
+	if errors.Is(err, parser.ErrNoPassData) {
+		if err := showUnknownPassStatus(mgr, passConditionsPath); err != nil {
+			log.Printf("Warning: Failed to create unknown pass status graphic: %v", err)
+		}
+	} else if err != nil {
 		log.Printf("Warning: Failed to parse WSDOT status: %v", err)
 		// Remove any existing pass conditions file on parse failure
 		os.Remove(passConditionsPath)
 		log.Printf("Pass status unknown - no graphic displayed")
 	} else {
+		// Record status in pass history and log any transitions
+		recordPassHistory(mgr, passResults[0].Pass.HTMLPath, passStatus)
+
 		// Determine closure status
 		eastStatus := strings.ToLower(passStatus.East)
 		westStatus := strings.ToLower(passStatus.West)
@@ -183,6 +279,13 @@ func runRender() error {
 
 		log.Printf("Pass Status - East: %s (closed: %v), West: %s (closed: %v)",
 			passStatus.East, isEastClosed, passStatus.West, isWestClosed)
+		if r := passStatus.Reopening; r != nil && passStatus.IsClosed {
+			if !r.Time.IsZero() {
+				log.Printf("Estimated reopening: %s (%q)", r.Time.Format("Mon Jan 2 3:04 PM MST"), r.Text)
+			} else {
+				log.Printf("Reopening: %q", r.Text)
+			}
+		}
 
 		// Only show a graphic if the pass has closures
 		if !isEastClosed && !isWestClosed {
@@ -190,25 +293,36 @@ func runRender() error {
 			// Remove any existing pass_conditions.png file
 			os.Remove(passConditionsPath)
 			log.Printf("Pass is open - no status graphic displayed")
-		} else {
-			// Get the appropriate graphic path for closure
-			graphicPath := mgr.GetPassStatusGraphicPath(isEastClosed, isWestClosed)
-
-			// Copy the graphic to the pass conditions path
-			if err := copyFile(graphicPath, passConditionsPath); err != nil {
-				log.Printf("Warning: Failed to copy pass status graphic from %s: %v", graphicPath, err)
-				// Last resort: create empty image
-				if err := pkgimage.CreateEmptyImage(250, 200, passConditionsPath); err != nil {
-					log.Printf("Warning: Failed to create empty pass conditions image: %v", err)
-				}
+		} else if showsClosureDetails(passStatus) {
+			// The static graphics can't show closure details, so render the status panel instead
+			if err := tr.RenderPassStatus(passStatus, 250, 200, passConditionsPath); err != nil {
+				log.Printf("Warning: Failed to render pass status panel: %v", err)
+				showPassStatusGraphic(mgr, isEastClosed, isWestClosed, passConditionsPath)
 			} else {
-				log.Printf("Pass status graphic copied: %s -> %s", graphicPath, passConditionsPath)
+				log.Printf("Pass status panel rendered: %s", passConditionsPath)
 			}
+		} else {
+			showPassStatusGraphic(mgr, isEastClosed, isWestClosed, passConditionsPath)
 		}
 	}
 
+	if err := runResults.Save(); err != nil {
+		log.Printf("Warning: %v", err)
+	}
+
+	// Multi-pass status strip
+	if err := tr.RenderPassStrip(passResults, mgr.GetPassStripImagePath()); err != nil {
+		log.Printf("Warning: Failed to render pass status strip: %v", err)
+	} else {
+		log.Printf("Pass status strip rendered: %s", mgr.GetPassStripImagePath())
+	}
+
+	// Area Forecast Discussion short term panel
+	renderAFDPanel(mgr, prsr, tr)
+
 	// Composite the image
 	compositor := pkgimage.NewCompositor(mgr)
+	compositor.SetPassResults(passResults)
 	if err := compositor.Render(outputPath); err != nil {
 		return fmt.Errorf("composite failed: %w", err)
 	}
@@ -216,3 +330,112 @@ func runRender() error {
 	log.Printf("Composite image saved: %s", outputPath)
 	return nil
 }
+
+// showsClosureDetails reports whether the pass status panel has closure details to show
+func showsClosureDetails(passStatus *parser.PassStatus) bool {
+	return passStatus.IsClosed && (!passStatus.ClosedSince.IsZero() || passStatus.Reopening != nil)
+}
+
+// showPassStatusGraphic copies the static closure graphic to the pass conditions path
+// Falls back to an empty image if the graphic is not available
+func showPassStatusGraphic(mgr *assets.Manager, isEastClosed, isWestClosed bool, passConditionsPath string) {
+	graphicPath := mgr.GetPassStatusGraphicPath(isEastClosed, isWestClosed)
+	if err := copyFile(graphicPath, passConditionsPath); err != nil {
+		log.Printf("Warning: Failed to copy pass status graphic from %s: %v", graphicPath, err)
+		// Last resort: create empty image
+		if err := pkgimage.CreateEmptyImage(250, 200, passConditionsPath); err != nil {
+			log.Printf("Warning: Failed to create empty pass conditions image: %v", err)
+		}
+		return
+	}
+	log.Printf("Pass status graphic copied: %s -> %s", graphicPath, passConditionsPath)
+}
+
+// showUnknownPassStatus displays the "unknown" pass status graphic
+// Falls back to a rendered caption if the graphic is not available
+func showUnknownPassStatus(mgr *assets.Manager, passConditionsPath string) error {
+	graphicPath := mgr.GetPassStatusUnknownGraphicPath()
+	err := copyFile(graphicPath, passConditionsPath)
+	if err == nil {
+		log.Printf("Pass status unknown - graphic copied: %s -> %s", graphicPath, passConditionsPath)
+		return nil
+	}
+	log.Printf("Warning: Failed to copy unknown status graphic from %s: %v", graphicPath, err)
+
+	tr := pkgimage.NewTextRenderer()
+	if err := tr.RenderCaption("Stevens Pass status unknown", 250, 60, passConditionsPath); err != nil {
+		return err
+	}
+	log.Printf("Pass status unknown - caption rendered to %s", passConditionsPath)
+	return nil
+}
+
+// recordPassHistory appends the parsed status to the pass history log
+// and fills in ClosedSince so the renderer can show the closure duration
+// A status is recorded once per scrape: only when the pass HTML is newer than the last record
+func recordPassHistory(mgr *assets.Manager, htmlPath string, passStatus *parser.PassStatus) {
+	store, err := history.Load(mgr.GetPassHistoryPath())
+	if err != nil {
+		log.Printf("Warning: %v", err)
+	}
+
+	info, err := os.Stat(htmlPath)
+	if err != nil {
+		log.Printf("Warning: Failed to stat pass HTML: %v", err)
+		return
+	}
+	scraped := info.ModTime()
+
+	last, ok := store.Last()
+	if ok && !scraped.After(last.Time) {
+		log.Printf("Pass HTML unchanged since %s - not recording history", last.Time.Format("Jan 2 3:04 PM"))
+		if since, ok := store.ClosedSince(); ok {
+			passStatus.ClosedSince = since
+		}
+		return
+	}
+
+	for _, event := range store.Record(passStatus, scraped) {
+		log.Printf("Pass %s %s: %q -> %q", event.Direction, event.Type, event.From, event.To)
+	}
+
+	if since, ok := store.ClosedSince(); ok {
+		passStatus.ClosedSince = since
+		log.Printf("Pass closed for %s (since %s)", history.FormatDuration(time.Since(since)), since.Format("Jan 2 3:04 PM"))
+	}
+
+	if err := store.Save(); err != nil {
+		log.Printf("Warning: %v", err)
+	}
+}
+
+// renderAFDPanel renders the short term section of the Area Forecast Discussion
+// Removes any stale panel if the product is missing or can't be parsed
+func renderAFDPanel(mgr *assets.Manager, prsr *parser.Parser, tr *pkgimage.TextRenderer) {
+	panel := mgr.GetAFDPanel()
+
+	afd, err := prsr.ParseAFDFile(panel.TextPath)
+	if err != nil {
+		log.Printf("Warning: Failed to parse forecast discussion: %v", err)
+		os.Remove(panel.ImagePath)
+		return
+	}
+
+	section, ok := afd.Section(parser.SectionShortTerm)
+	if !ok {
+		log.Printf("Warning: Forecast discussion has no %s section", parser.SectionShortTerm)
+		os.Remove(panel.ImagePath)
+		return
+	}
+
+	title := "Forecast Discussion - Short Term"
+	if !afd.IssuedAt.IsZero() {
+		title += " (" + afd.IssuedAt.Format("Jan 2 3:04 PM") + ")"
+	}
+
+	if err := tr.RenderTextPanel(title, section.Text, panel.Box.Dx(), panel.Box.Dy(), panel.ImagePath); err != nil {
+		log.Printf("Warning: Failed to render forecast discussion panel: %v", err)
+		return
+	}
+	log.Printf("Forecast discussion panel rendered: %s", panel.ImagePath)
+}
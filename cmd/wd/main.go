@@ -7,12 +7,14 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/trodemaster/weatherdesktop/pkg/assets"
 	"github.com/trodemaster/weatherdesktop/pkg/desktop"
 	"github.com/trodemaster/weatherdesktop/pkg/docker"
+	"github.com/trodemaster/weatherdesktop/pkg/history"
 )
 
 var (
@@ -29,11 +31,14 @@ var (
 	debugFlag       = flag.Bool("debug", false, "Enable debug output")
 	scrapeTargetFlag = flag.String("scrape-target", "", "Test specific scrape target by name")
 	listTargetsFlag = flag.Bool("list-targets", false, "List all available scrape targets and exit")
+	historyCountFlag = flag.Int("n", 20, "Number of transitions to show with the history command")
+	scrapeParallelFlag = flag.Int("scrape-parallel", 0, "Number of targets to scrape concurrently (0 = worker default)")
 )
 
 func main() {
 	flag.Usage = func() {
-		fmt.Fprintf(os.Stderr, "USAGE: wd [options]\n\n")
+		fmt.Fprintf(os.Stderr, "USAGE: wd [options]\n")
+		fmt.Fprintf(os.Stderr, "       wd [-n <count>] history\n\n")
 		fmt.Fprintf(os.Stderr, "Running wd without options will collect all assets,\n")
 		fmt.Fprintf(os.Stderr, "render and set the desktop image to the output.\n\n")
 		fmt.Fprintf(os.Stderr, "Individual options provided for debugging specific functions.\n\n")
@@ -53,15 +58,28 @@ func main() {
 		fmt.Fprintf(os.Stderr, "   -debug                Enable debug output\n")
 		fmt.Fprintf(os.Stderr, "   -list-targets         List all available scrape targets\n")
 		fmt.Fprintf(os.Stderr, "   -scrape-target <name> Test specific scrape target (e.g., \"Weather.gov Hourly\")\n")
+		fmt.Fprintf(os.Stderr, "   -scrape-parallel <n>  Number of targets to scrape concurrently\n")
+		fmt.Fprintf(os.Stderr, "\nCOMMANDS:\n")
+		fmt.Fprintf(os.Stderr, "   history               List recent pass status transitions\n")
+		fmt.Fprintf(os.Stderr, "   -n <count>            Number of transitions to list (default: 20)\n")
 		fmt.Fprintf(os.Stderr, "\nEXAMPLES:\n")
 		fmt.Fprintf(os.Stderr, "   wd -s -scrape-target \"NWAC Stevens\" -debug\n")
 		fmt.Fprintf(os.Stderr, "   wd -s -debug\n")
 		fmt.Fprintf(os.Stderr, "   wd -set-desktop ./rendered/hud-251102-1056.jpg\n")
 		fmt.Fprintf(os.Stderr, "   wd -p -clear-cache              # Set desktop with full cache cleanup\n")
+		fmt.Fprintf(os.Stderr, "   wd -n 50 history                # Show last 50 pass transitions\n")
 	}
 	
 	flag.Parse()
 
+	// Handle history command (special case - exits after listing)
+	if flag.Arg(0) == "history" {
+		if err := listPassHistory(*historyCountFlag); err != nil {
+			log.Fatalf("Failed to list pass history: %v", err)
+		}
+		return
+	}
+
 	// Handle list-targets flag (special case - exits after listing)
 	if *listTargetsFlag {
 		listScrapeTargets()
@@ -193,6 +211,9 @@ func main() {
 		if *scrapeTargetFlag != "" {
 			args = append(args, "--target", *scrapeTargetFlag)
 		}
+		if *scrapeParallelFlag > 0 {
+			args = append(args, "--parallel", strconv.Itoa(*scrapeParallelFlag))
+		}
 		
 		if err := dockerClient.Exec(args...); err != nil {
 			log.Fatalf("Failed to scrape sites: %v", err)
@@ -217,7 +238,12 @@ func main() {
 	if doCrop {
 		log.Println("Cropping images...")
 		
-		if err := dockerClient.Exec("/app/wd-worker", "crop"); err != nil {
+		args := []string{"/app/wd-worker", "crop"}
+		if *debugFlag {
+			args = append(args, "--debug")
+		}
+		
+		if err := dockerClient.Exec(args...); err != nil {
 			log.Fatalf("Failed to crop images: %v", err)
 		}
 		
@@ -596,16 +622,23 @@ func listScrapeTargets() {
 		fmt.Printf("   URL: %s\n", target.URL)
 		fmt.Printf("   Selector: %s\n", target.Selector)
 		fmt.Printf("   Default Wait: %dms\n", target.WaitTime)
+		if target.Browser != "" {
+			fmt.Printf("   Browser: %s\n", target.Browser)
+		}
+		if target.Extract != nil {
+			fmt.Printf("   Extract: %s\n", target.Extract.Kind)
+		}
 		fmt.Printf("   Output: %s\n", filepath.Base(target.OutputPath))
 		fmt.Println()
 	}
 	
-	htmlTarget := mgr.GetWSDOTHTMLTarget()
-	fmt.Printf("HTML Extraction Target:\n")
-	fmt.Printf("   %s\n", htmlTarget.Name)
-	fmt.Printf("   URL: %s\n", htmlTarget.URL)
-	fmt.Printf("   Output: %s\n", filepath.Base(htmlTarget.OutputPath))
-	fmt.Println()
+	fmt.Printf("HTML Extraction Targets:\n")
+	for _, htmlTarget := range mgr.GetWSDOTHTMLTargets() {
+		fmt.Printf("   %s\n", htmlTarget.Name)
+		fmt.Printf("   URL: %s\n", htmlTarget.URL)
+		fmt.Printf("   Output: %s\n", filepath.Base(htmlTarget.OutputPath))
+		fmt.Println()
+	}
 	
 	fmt.Println("Usage:")
 	fmt.Println("  wd -s -scrape-target \"<name>\" -debug")
@@ -615,3 +648,46 @@ func listScrapeTargets() {
 	fmt.Println("  wd -s -scrape-target \"NWAC\" -debug     # Matches all NWAC targets")
 }
 
+
+// listPassHistory lists recent pass status transitions from the history log
+func listPassHistory(count int) error {
+	scriptDir, err := filepath.Abs(filepath.Dir(os.Args[0]))
+	if err != nil {
+		scriptDir = "."
+	}
+
+	mgr := assets.NewManager(scriptDir)
+	store, err := history.Load(mgr.GetPassHistoryPath())
+	if err != nil {
+		return err
+	}
+
+	last, ok := store.Last()
+	if !ok {
+		fmt.Println("No pass history recorded yet")
+		return nil
+	}
+
+	fmt.Printf("Current Status (as of %s):\n", last.Time.Local().Format("Mon Jan 2 3:04 PM"))
+	fmt.Printf("   East: %s\n", last.East)
+	fmt.Printf("   West: %s\n", last.West)
+	if since, ok := store.ClosedSince(); ok {
+		fmt.Printf("   Closed for %s (since %s)\n", history.FormatDuration(time.Since(since)), since.Local().Format("Mon Jan 2 3:04 PM"))
+	}
+	fmt.Println()
+
+	events := store.RecentEvents(count)
+	if len(events) == 0 {
+		fmt.Println("No transitions recorded")
+		return nil
+	}
+
+	fmt.Println("Recent Transitions:")
+	fmt.Println()
+	for _, event := range events {
+		fmt.Printf("%s  %-4s  %s\n", event.Time.Local().Format("Mon Jan 2 3:04 PM"), event.Direction, event.Type)
+		fmt.Printf("   %s -> %s\n", event.From, event.To)
+	}
+
+	return nil
+}
@@ -2,7 +2,10 @@ package assets
 
 import (
 	"image"
+	"image/color"
+	"log"
 	"path/filepath"
+	"time"
 )
 
 // Manager handles asset paths and configurations
@@ -27,7 +30,135 @@ type ScrapeTarget struct {
 	URL        string
 	Selector   string
 	OutputPath string
-	WaitTime   int // milliseconds
+	WaitTime   int          // milliseconds, default timeout for selector wait steps
+	Timeout    int          // milliseconds, overall limit for this target (0 = scraper default)
+	Waits      []WaitStep   // Run in order after navigation (nil = wait for Selector to be visible)
+	Actions    []PageAction // Run in order after the wait steps, before capture
+
+	// Browser context settings (zero values keep the browser defaults)
+	ViewportWidth     int
+	ViewportHeight    int
+	DeviceScaleFactor float64
+	ColorScheme       string // "light", "dark" or "no-preference"
+
+	Browser string // BrowserWebKit (default), BrowserChromium or BrowserFirefox
+
+	Block *BlockList // Requests to abort (nil = DefaultBlockList, &BlockList{} = block nothing)
+
+	// ExpectedSize is the approximate screenshot size in CSS pixels, used to reject
+	// captures of the wrong element or a half-rendered page (zero = no size check)
+	ExpectedSize image.Point
+
+	Extract *Extract // Save data from the matched elements instead of a screenshot
+}
+
+// ExtractKind selects what an extraction target saves
+type ExtractKind string
+
+const (
+	ExtractInnerHTML ExtractKind = "inner_html" // Element innerHTML
+	ExtractInnerText ExtractKind = "inner_text" // Rendered text (innerText)
+	ExtractAttribute ExtractKind = "attribute"  // Value of Attribute
+	ExtractJSON      ExtractKind = "json"       // JSON result of Script
+)
+
+// Extract configures a data extraction target
+type Extract struct {
+	Kind      ExtractKind
+	Attribute string // Attribute name for ExtractAttribute, e.g. "src"
+	Script    string // JS function for ExtractJSON, called with the array of matched elements
+	All       bool   // Every matched element (one per line, or a JSON array) instead of the first
+}
+
+// BlockList selects requests aborted during a scrape
+// Hosts match exactly or as a parent domain: "doubleclick.net" also blocks "stats.g.doubleclick.net"
+type BlockList struct {
+	ResourceTypes []string // Playwright resource types, e.g. "media", "font"
+	Hosts         []string
+}
+
+// DefaultBlockList blocks analytics, ads, web fonts and video, none of which appear in captures
+func DefaultBlockList() *BlockList {
+	return &BlockList{
+		ResourceTypes: []string{"media", "font"},
+		Hosts: []string{
+			"google-analytics.com",
+			"googletagmanager.com",
+			"googlesyndication.com",
+			"doubleclick.net",
+			"facebook.net",
+			"hotjar.com",
+			"nr-data.net",
+			"dap.digitalgov.gov", // weather.gov Digital Analytics Program
+			"youtube.com",
+			"ytimg.com",
+		},
+	}
+}
+
+// Browser engines available to scrape targets
+const (
+	BrowserWebKit   = "webkit"
+	BrowserChromium = "chromium"
+	BrowserFirefox  = "firefox"
+)
+
+// ActionKind identifies a pre-capture page action
+type ActionKind string
+
+const (
+	ActionClickIfPresent ActionKind = "click_if_present" // Click Selector if it exists (e.g. cookie consent "Accept")
+	ActionRemove         ActionKind = "remove"           // Remove all elements matching Selector
+	ActionInjectCSS      ActionKind = "inject_css"       // Add CSS to the page (e.g. hide sticky headers)
+	ActionScrollIntoView ActionKind = "scroll_into_view" // Scroll Selector (default: target selector) into view
+)
+
+// PageAction is a single declarative page change applied before capture
+type PageAction struct {
+	Kind     ActionKind
+	Selector string
+	CSS      string // Stylesheet for ActionInjectCSS
+}
+
+// WaitKind identifies what a wait step waits for
+type WaitKind string
+
+const (
+	WaitSelectorVisible WaitKind = "selector_visible" // Selector (default: target selector) is visible
+	WaitChildSelector   WaitKind = "child_selector"   // Selector inside the target element is visible
+	WaitNetworkIdle     WaitKind = "network_idle"     // No network requests for 500ms
+	WaitJSPredicate     WaitKind = "js_predicate"     // Script returns true; called with the target selector
+	WaitDelay           WaitKind = "delay"            // Fixed sleep of Timeout milliseconds
+	WaitImageLoaded     WaitKind = "image_loaded"     // <img> (default: target selector) is complete with a non-zero size
+)
+
+// WaitStep is a single declarative wait run before capture
+type WaitStep struct {
+	Kind     WaitKind
+	Selector string     // Selector for selector, child and image steps
+	Script   string     // JS function for WaitJSPredicate, e.g. "(sel) => !!document.querySelector(sel)"
+	Timeout  int        // milliseconds (0 = WaitTime for selector steps, 10s otherwise)
+	Optional bool       // Log and continue if the step times out
+	Fallback []WaitStep // Run instead if this step times out
+}
+
+// PassDefinition defines a WSDOT mountain pass
+// All WSDOT pass pages share one template, so each pass only needs its URL slug
+type PassDefinition struct {
+	Name     string // Display name, e.g. "Stevens Pass"
+	Slug     string // WSDOT URL slug, e.g. "stevens"
+	HTMLPath string // Extracted pass report HTML
+	Source   string // Name of the scrape target that extracts HTMLPath
+}
+
+// TextPanel defines an NWS text product rendered as a panel layer
+type TextPanel struct {
+	Name      string
+	Office    string          // NWS office identifier, e.g. "SEW"
+	Product   string          // NWS product type, e.g. "AFD"
+	TextPath  string          // Raw product text
+	ImagePath string          // Rendered panel
+	Box       image.Rectangle // Panel position and size on the canvas
 }
 
 // DownloadTarget defines an image download target
@@ -44,12 +175,201 @@ type Asset struct {
 	OutputPath string
 	CropRect   image.Rectangle
 	TargetSize image.Point
+	AutoCrop   *AutoCrop    // Detect the crop from the image content (CropRect becomes the search region)
+	Fit        FitMode      // How the crop is scaled to TargetSize (default: stretch)
+	Gravity    Gravity      // Anchor for letterboxing (contain) and trimming (cover)
+	Letterbox  color.Color  // Contain: fill for the bars (nil = transparent, output is the fitted image only)
+	Encoding   Encoding     // Output format and quality
+	Adjust     []Adjustment // Tone/color/sharpening steps, applied in order after resizing
+}
+
+// AdjustKind selects an image adjustment step
+type AdjustKind string
+
+const (
+	AdjustAutoLevels         AdjustKind = "auto_levels"         // Stretch each channel to full range (removes color casts); Clip
+	AdjustStretch            AdjustKind = "stretch"             // Stretch luminance to full range, same curve for all channels; Clip
+	AdjustGamma              AdjustKind = "gamma"               // Amount > 1 brightens midtones, < 1 darkens
+	AdjustBrightnessContrast AdjustKind = "brightness_contrast" // Brightness and Contrast, -1 to 1
+	AdjustSaturation         AdjustKind = "saturation"          // Amount is the factor (0 = gray, 1 = unchanged)
+	AdjustUnsharp            AdjustKind = "unsharp"             // Unsharp mask; Radius, Amount, Threshold
+)
+
+// Adjustment is one step of an asset's adjustment pipeline
+// Only the fields used by Kind are read
+type Adjustment struct {
+	Kind       AdjustKind
+	Amount     float64 // Gamma, saturation factor or unsharp strength
+	Brightness float64 // Added to every channel (-1 to 1 = full range)
+	Contrast   float64 // Slope change around mid gray (-1 = flat, 0 = unchanged, 1 = double)
+	Clip       float64 // Levels/stretch: share of darkest and brightest pixels clipped (0 = 0.5%)
+	Radius     float64 // Unsharp: Gaussian sigma in pixels (0 = 1)
+	Threshold  int     // Unsharp: minimum difference (0-255) that gets sharpened, keeps noise down
+}
+
+// ImageFormat selects an output encoder
+type ImageFormat string
+
+const (
+	FormatAuto ImageFormat = ""     // From the output extension (.png = PNG, anything else = JPEG)
+	FormatJPEG ImageFormat = "jpeg" // No alpha; best for photos and webcams
+	FormatPNG  ImageFormat = "png"  // Lossless with alpha; best for text and graphics
+)
+
+// Encoding configures how an image is written
+type Encoding struct {
+	Format  ImageFormat
+	Quality int  // JPEG quality 1-100 (0 = 90)
+	Colors  int  // PNG: quantize to a palette of at most this many colors, 2-256 (0 = full color)
+	Dither  bool // PNG palette: Floyd-Steinberg dithering (smoother gradients, noisier text)
+}
+
+// FitMode controls how an asset's crop is scaled to its TargetSize
+type FitMode string
+
+const (
+	FitStretch FitMode = ""        // Scale to exactly TargetSize, ignoring aspect ratio
+	FitContain FitMode = "contain" // Fit inside TargetSize, letterbox the remainder
+	FitCover   FitMode = "cover"   // Fill TargetSize, trim the overflow at Gravity
+	FitWidth   FitMode = "width"   // Scale to TargetSize.X, height follows the aspect ratio
+	FitHeight  FitMode = "height"  // Scale to TargetSize.Y, width follows the aspect ratio
+)
+
+// Gravity anchors an image inside a larger (or smaller) box
+type Gravity string
+
+const (
+	GravityCenter      Gravity = ""
+	GravityTop         Gravity = "top"
+	GravityBottom      Gravity = "bottom"
+	GravityLeft        Gravity = "left"
+	GravityRight       Gravity = "right"
+	GravityTopLeft     Gravity = "top_left"
+	GravityTopRight    Gravity = "top_right"
+	GravityBottomLeft  Gravity = "bottom_left"
+	GravityBottomRight Gravity = "bottom_right"
+)
+
+// AutoCrop configures content-aware cropping
+// The background color is the most common color along the edges of the search region;
+// the crop is the bounding box of pixels that differ from it
+type AutoCrop struct {
+	Tolerance int         // Max per-channel difference from the background (0 = 24, absorbs JPEG noise)
+	Padding   int         // Pixels kept around the detected content
+	MinSize   image.Point // Grow a smaller detection around its center (zero = no minimum)
+	MaxSize   image.Point // Shrink a larger detection from the bottom/right (zero = no maximum)
+
+	// Template is an optional reference image (e.g. a section heading) found in the input
+	// before trimming; CropRect is shifted by how far it moved from TemplateAt
+	Template   string
+	TemplateAt image.Point // Template position in the layout CropRect was measured against
 }
 
 // CompositeLayer defines a layer in the composite image
 type CompositeLayer struct {
 	ImagePath string
 	Position  image.Point
+	Size      image.Point // Box the image is placed in (zero = the image's own size)
+	Gravity   Gravity     // Alignment of the image inside Size; overflow is clipped
+	Night     NightMode   // What a webcam layer shows when it is dark after civil dusk
+	Style     *LayerStyle // Optional border, corners, shadow, opacity and backing plate
+	Caption   *Caption    // Optional label with the source name and capture time
+	Backdrop  *Backdrop   // Optional blur/dim of this layer behind the layers above it
+}
+
+// Backdrop softens a background layer so the layers above it stand out
+// Applied once the layer is drawn, before the layers above it
+type Backdrop struct {
+	Scope    BackdropScope
+	Blur     float64 // Gaussian sigma in pixels (0 = no blur)
+	Dim      float64 // Share of brightness removed, 0-1
+	Padding  int     // How far the treated area extends around each layer above (BackdropBehind)
+	Vignette float64 // Darkening of the whole layer toward the corners, 0-1 (0 = none)
+}
+
+// BackdropScope selects where a backdrop's blur and dim apply
+type BackdropScope string
+
+const (
+	BackdropBehind BackdropScope = ""       // Only under the layers above, plus Padding
+	BackdropGlobal BackdropScope = "global" // The whole canvas
+)
+
+// Caption labels a composite layer
+type Caption struct {
+	Text      string
+	TimeFrom  string // File whose modification time is the capture time ("" = no time); downloads carry HTTP Last-Modified
+	Placement CaptionPlacement
+	Style     CaptionStyle
+}
+
+// CaptionPlacement positions a caption relative to its layer
+type CaptionPlacement string
+
+const (
+	CaptionTopLeft     CaptionPlacement = ""
+	CaptionTopRight    CaptionPlacement = "top_right"
+	CaptionBottomLeft  CaptionPlacement = "bottom_left"
+	CaptionBottomRight CaptionPlacement = "bottom_right"
+	CaptionBelow       CaptionPlacement = "below" // Outside the layer, under its left edge
+)
+
+// CaptionStyle keeps caption text readable on any image
+type CaptionStyle string
+
+const (
+	CaptionBacked   CaptionStyle = ""         // White text on a translucent dark plate
+	CaptionOutlined CaptionStyle = "outlined" // White text with a dark outline, no plate
+)
+
+// LayerStyle configures effects drawn with a composite layer
+// Effects follow the layer's visible area; the shadow also follows the image's own transparency
+type LayerStyle struct {
+	CornerRadius int         // Anti-aliased rounded corners (0 = square)
+	BorderWidth  int         // Drawn inside the layer's edge (0 = none)
+	BorderColor  color.Color // nil = white
+	Opacity      float64     // Layer opacity 0-1 (0 = unset, fully opaque)
+	Shadow       *Shadow
+	Plate        *Plate
+}
+
+// Shadow is a blurred drop shadow under a layer
+type Shadow struct {
+	Offset image.Point
+	Blur   float64     // Gaussian sigma in pixels (0 = hard edge)
+	Color  color.Color // nil = 55% black
+}
+
+// Plate is a translucent backing panel around a layer (rounded like the layer)
+type Plate struct {
+	Padding int
+	Color   color.Color // nil = 45% black
+}
+
+// NightMode selects how a webcam layer is shown after dark
+// A layer counts as dark when the sun is below civil twilight and the frame's mean
+// luminance is low, so cams lit for night skiing keep showing the live frame
+type NightMode string
+
+const (
+	NightShow      NightMode = ""           // Composite the current frame as is
+	NightLastLight NightMode = "last_light" // Show the last daylight frame, labelled "Last light <time>"
+	NightDim       NightMode = "dim"        // Dim the current frame and label it "Night"
+	NightHide      NightMode = "hide"       // Leave the layer out
+)
+
+// Location is the place sun position is computed for
+type Location struct {
+	Name      string
+	Latitude  float64 // Degrees north
+	Longitude float64 // Degrees east
+}
+
+// HUD configures the status strip the compositor draws over the wallpaper
+type HUD struct {
+	Region     image.Rectangle // Canvas area of the strip; text is fitted inside
+	StaleAfter time.Duration   // Sources whose data is older count as stale
+	Background color.Color     // Strip color (nil = translucent black)
 }
 
 // GetDownloadTargets returns all download targets
@@ -117,25 +437,33 @@ func (m *Manager) GetDownloadTargets() []DownloadTarget {
 func (m *Manager) GetScrapeTargets() []ScrapeTarget {
 	return []ScrapeTarget{
 		{
-			Name:       "Weather.gov Hourly Forecast",
-			URL:        "https://forecast.weather.gov/MapClick.php?lat=47.7456&lon=-121.0892&unit=0&lg=english&FcstType=graphical",
-			Selector:   "img[src*=\"meteograms/Plotter.php\"]",
-			OutputPath: filepath.Join(m.AssetsDir, "weather_gov_hourly_forecast.png"),
-			WaitTime:   5000,
+			Name:         "Weather.gov Hourly Forecast",
+			URL:          "https://forecast.weather.gov/MapClick.php?lat=47.7456&lon=-121.0892&unit=0&lg=english&FcstType=graphical",
+			Selector:     "img[src*=\"meteograms/Plotter.php\"]",
+			OutputPath:   filepath.Join(m.AssetsDir, "weather_gov_hourly_forecast.png"),
+			WaitTime:     5000,
+			ExpectedSize: image.Point{X: 800, Y: 871}, // Crop region in GetCropAssets
+			Waits: []WaitStep{
+				{Kind: WaitSelectorVisible, Optional: true},
+				{Kind: WaitImageLoaded, Optional: true}, // Meteogram is generated on request and can lag the <img> tag
+			},
 		},
 		{
-			Name:       "Weather.gov Extended Forecast",
-			URL:        "https://forecast.weather.gov/MapClick.php?lat=47.7456&lon=-121.0892",
-			Selector:   "#seven-day-forecast",
-			OutputPath: filepath.Join(m.AssetsDir, "weather_gov_extended_forecast.png"),
-			WaitTime:   1000,
+			Name:         "Weather.gov Extended Forecast",
+			URL:          "https://forecast.weather.gov/MapClick.php?lat=47.7456&lon=-121.0892",
+			Selector:     "#seven-day-forecast",
+			OutputPath:   filepath.Join(m.AssetsDir, "weather_gov_extended_forecast.png"),
+			WaitTime:     1000,
+			ExpectedSize: image.Point{X: 1146, Y: 400},
 		},
 		{
-			Name:       "NWAC Stevens Observations",
-			URL:        "https://nwac.us/data-portal/graph/21/",
-			Selector:   "#post-146 > div",
-			OutputPath: filepath.Join(m.AssetsDir, "nwac_stevens_observations.png"),
-			WaitTime:   15000, // Increased for slow NWAC site
+			Name:         "NWAC Stevens Observations",
+			URL:          "https://nwac.us/data-portal/graph/21/",
+			Selector:     "#post-146 > div",
+			OutputPath:   filepath.Join(m.AssetsDir, "nwac_stevens_observations.png"),
+			WaitTime:     15000, // Increased for slow NWAC site
+			ExpectedSize: image.Point{X: 1140, Y: 1439},
+			Waits:        nwacWaits(),
 		},
 		{
 			Name:       "NWAC Avalanche Forecast",
@@ -143,26 +471,136 @@ func (m *Manager) GetScrapeTargets() []ScrapeTarget {
 			Selector:   "#nac-tab-resizer > div > div:nth-child(1) > div > div.nac-danger.nac-mb-4 > div.nac-row > div.nac-dangerToday.nac-col-lg-8.nac-mb-3 > div.nac-dangerGraphic",
 			OutputPath: filepath.Join(m.AssetsDir, "nwac_stevens_avalanche_forcast.png"),
 			WaitTime:   15000, // Increased for slow NWAC site
+			Waits:      nwacWaits(),
 		},
 		{
-			Name:       "NWAC Avalanche Forecast Map",
-			URL:        "https://nwac.us",
-			Selector:   "#danger-map-widget",
-			OutputPath: filepath.Join(m.AssetsDir, "nwac_avalanche_forcast.png"),
-			WaitTime:   15000, // Increased for slow NWAC site
+			Name:         "NWAC Avalanche Forecast Map",
+			URL:          "https://nwac.us",
+			Selector:     "#danger-map-widget",
+			OutputPath:   filepath.Join(m.AssetsDir, "nwac_avalanche_forcast.png"),
+			WaitTime:     15000, // Increased for slow NWAC site
+			ExpectedSize: image.Point{X: 465, Y: 630},
+			Waits:        nwacWaits(),
 		},
 	}
 }
 
-// GetWSDOTHTMLTarget returns the WSDOT pass status HTML extraction target
-func (m *Manager) GetWSDOTHTMLTarget() ScrapeTarget {
-	return ScrapeTarget{
-		Name:       "WSDOT Stevens Pass Status",
-		URL:        "https://wsdot.com/travel/real-time/mountainpasses/stevens",
-		Selector:   ".full-width.column-container.mountain-pass .column-1",
-		OutputPath: filepath.Join(m.AssetsDir, "wsdot_stevens_pass.html"),
-		WaitTime:   10000, // 10 seconds for Vue.js page to fully render
+// wsdotReportRendered is true once the Vue app has filled in the travel restrictions
+const wsdotReportRendered = `(sel) => {
+	const el = document.querySelector(sel);
+	return !!el && /eastbound/i.test(el.innerText);
+}`
+
+// cameraCaption captions a webcam layer with its download target's name and capture time
+// file is the layer image in AssetsDir; its target is found by output path, directly or through
+// the crop asset that produces it. A layer without one is left uncaptioned
+func (m *Manager) cameraCaption(file string, style CaptionStyle) *Caption {
+	imagePath := filepath.Join(m.AssetsDir, file)
+	source := imagePath
+	for _, asset := range m.GetCropAssets() {
+		if asset.OutputPath == imagePath {
+			source = asset.InputPath
+			break
+		}
+	}
+	for _, target := range m.GetDownloadTargets() {
+		if target.OutputPath == source {
+			return &Caption{Text: target.Name, TimeFrom: target.OutputPath, Style: style}
+		}
+	}
+	log.Printf("Warning: camera layer %s has no download target - no caption", imagePath)
+	return nil
+}
+
+// panelStyle separates photo and forecast panels from the satellite background
+func panelStyle() *LayerStyle {
+	return &LayerStyle{
+		CornerRadius: 10,
+		BorderWidth:  2,
+		BorderColor:  color.NRGBA{255, 255, 255, 170},
+		Shadow:       &Shadow{Offset: image.Point{X: 6, Y: 8}, Blur: 8},
+	}
+}
+
+// webcamAdjust brightens murky (dawn, dusk, fog) webcam frames and restores detail lost to downscaling
+func webcamAdjust() []Adjustment {
+	return []Adjustment{
+		{Kind: AdjustAutoLevels},
+		{Kind: AdjustUnsharp, Radius: 1, Amount: 0.6, Threshold: 3},
+	}
+}
+
+// nwacWaits waits for the NWAC charts (canvas/svg) to render
+// Falls back to network idle if no chart element appears
+func nwacWaits() []WaitStep {
+	return []WaitStep{
+		{Kind: WaitSelectorVisible, Optional: true},
+		{
+			Kind:     WaitChildSelector,
+			Selector: "canvas, svg",
+			Timeout:  20000, // Chart rendering
+			Optional: true,
+			Fallback: []WaitStep{
+				{Kind: WaitNetworkIdle, Timeout: 15000, Optional: true},
+			},
+		},
+		{Kind: WaitDelay, Timeout: 1500}, // Animations/transitions
+	}
+}
+
+// GetPassDefinitions returns all configured mountain passes
+// The first pass is the primary pass (status graphic and history)
+func (m *Manager) GetPassDefinitions() []PassDefinition {
+	passes := []PassDefinition{
+		{
+			Name:     "Stevens Pass",
+			Slug:     "stevens",
+			HTMLPath: filepath.Join(m.AssetsDir, "wsdot_stevens_pass.html"),
+		},
+		{
+			Name:     "Snoqualmie Pass",
+			Slug:     "snoqualmie",
+			HTMLPath: filepath.Join(m.AssetsDir, "wsdot_snoqualmie_pass.html"),
+		},
+		{
+			Name:     "Blewett Pass",
+			Slug:     "blewett",
+			HTMLPath: filepath.Join(m.AssetsDir, "wsdot_blewett_pass.html"),
+		},
 	}
+	for i := range passes {
+		passes[i].Source = "WSDOT " + passes[i].Name + " Status"
+	}
+	return passes
+}
+
+// GetWSDOTHTMLTargets returns the WSDOT pass status HTML extraction targets
+// One target per pass definition, in the same order
+func (m *Manager) GetWSDOTHTMLTargets() []ScrapeTarget {
+	passes := m.GetPassDefinitions()
+	targets := make([]ScrapeTarget, 0, len(passes))
+	for _, pass := range passes {
+		targets = append(targets, ScrapeTarget{
+			Name:       pass.Source,
+			URL:        "https://wsdot.com/travel/real-time/mountainpasses/" + pass.Slug,
+			Selector:   ".full-width.column-container.mountain-pass .column-1",
+			OutputPath: pass.HTMLPath,
+			WaitTime:   10000, // 10 seconds for Vue.js page to fully render
+			Extract:    &Extract{Kind: ExtractInnerHTML},
+			Waits: []WaitStep{
+				{Kind: WaitSelectorVisible, Optional: true},
+				// Vue renders the container before the pass report is filled in;
+				// a missing report is caught by the parser's schema drift check
+				{Kind: WaitJSPredicate, Script: wsdotReportRendered, Timeout: 10000, Optional: true},
+			},
+		})
+	}
+	return targets
+}
+
+// GetWSDOTHTMLTarget returns the primary (Stevens Pass) HTML extraction target
+func (m *Manager) GetWSDOTHTMLTarget() ScrapeTarget {
+	return m.GetWSDOTHTMLTargets()[0]
 }
 
 // GetCropAssets returns all assets that need cropping and resizing
@@ -178,16 +616,17 @@ func (m *Manager) GetCropAssets() []Asset {
 		{
 			Name:       "NWAC Avalanche Forecast Map",
 			InputPath:  filepath.Join(m.AssetsDir, "nwac_avalanche_forcast.png"),
-			OutputPath: filepath.Join(m.AssetsDir, "nwac_avalanche_forcast_s.jpg"),
+			OutputPath: filepath.Join(m.AssetsDir, "nwac_avalanche_forcast_s.png"), // Keeps the map's transparency
 			CropRect:   image.Rect(65, 110, 465, 630),
 			TargetSize: image.Point{X: 400, Y: 520},
 		},
 		{
 			Name:       "NWAC Stevens Observations",
 			InputPath:  filepath.Join(m.AssetsDir, "nwac_stevens_observations.png"),
-			OutputPath: filepath.Join(m.AssetsDir, "nwac_stevens_observations_s.jpg"),
+			OutputPath: filepath.Join(m.AssetsDir, "nwac_stevens_observations_s.png"),
 			CropRect:   image.Rect(0, 0, 1140, 1439),
 			TargetSize: image.Point{X: 855, Y: 1079},
+			Encoding:   Encoding{Colors: 256}, // Text and chart lines stay sharp
 		},
 		{
 			Name:       "Stevens Pass Courtyard",
@@ -195,6 +634,7 @@ func (m *Manager) GetCropAssets() []Asset {
 			OutputPath: filepath.Join(m.AssetsDir, "stevenspasscourtyard_s.jpg"),
 			CropRect:   image.Rect(0, 0, 1920, 1080),
 			TargetSize: image.Point{X: 680, Y: 382},
+			Fit:        FitCover,
 		},
 		{
 			Name:       "Stevens Pass Snow Stake",
@@ -202,20 +642,27 @@ func (m *Manager) GetCropAssets() []Asset {
 			OutputPath: filepath.Join(m.AssetsDir, "stevenspasssnowstake_s.jpg"),
 			CropRect:   image.Rect(0, 0, 1920, 1080),
 			TargetSize: image.Point{X: 680, Y: 382},
+			Fit:        FitCover,
 		},
 		{
 			Name:       "Weather.gov Extended Forecast",
 			InputPath:  filepath.Join(m.AssetsDir, "weather_gov_extended_forecast.png"),
-			OutputPath: filepath.Join(m.AssetsDir, "weather_gov_extended_forecast_s.jpg"),
-			CropRect:   image.Rect(0, 100, 1146, 400),
+			OutputPath: filepath.Join(m.AssetsDir, "weather_gov_extended_forecast_s.png"),
+			CropRect:   image.Rect(0, 100, 1146, 400), // Search region below the heading
 			TargetSize: image.Point{X: 1146, Y: 300},
+			AutoCrop:   &AutoCrop{Padding: 4, MinSize: image.Pt(1100, 280), MaxSize: image.Pt(1146, 300)},
+			Fit:        FitContain, // A smaller detection is centered rather than stretched
+			Letterbox:  color.White,
+			Encoding:   Encoding{Colors: 256},
 		},
 		{
 			Name:       "Weather.gov Hourly Forecast",
 			InputPath:  filepath.Join(m.AssetsDir, "weather_gov_hourly_forecast.png"),
-			OutputPath: filepath.Join(m.AssetsDir, "weather_gov_hourly_forecast_s.jpg"),
+			OutputPath: filepath.Join(m.AssetsDir, "weather_gov_hourly_forecast_s.png"),
 			CropRect:   image.Rect(0, 0, 800, 871),
 			TargetSize: image.Point{X: 855, Y: 930},
+			Fit:        FitContain, // Placed in its 855x930 layer box by the compositor
+			Encoding:   Encoding{Colors: 256},
 		},
 		{
 			Name:       "WSDOT Stevens Pass (Big)",
@@ -230,6 +677,8 @@ func (m *Manager) GetCropAssets() []Asset {
 			OutputPath: filepath.Join(m.AssetsDir, "stevenspassjupiter_s.jpg"),
 			CropRect:   image.Rect(0, 0, 1280, 720),
 			TargetSize: image.Point{X: 1075, Y: 605},
+			Fit:        FitCover,
+			Adjust:     webcamAdjust(),
 		},
 		{
 			Name:       "Stevens Pass Skyline (Scaled)",
@@ -237,6 +686,8 @@ func (m *Manager) GetCropAssets() []Asset {
 			OutputPath: filepath.Join(m.AssetsDir, "stevenspassskyline_s.jpg"),
 			CropRect:   image.Rect(0, 0, 1280, 720),
 			TargetSize: image.Point{X: 1075, Y: 605},
+			Fit:        FitCover,
+			Adjust:     webcamAdjust(),
 		},
 		{
 			Name:       "Stevens Pass School (Scaled)",
@@ -244,6 +695,8 @@ func (m *Manager) GetCropAssets() []Asset {
 			OutputPath: filepath.Join(m.AssetsDir, "stevenspassschool_s.jpg"),
 			CropRect:   image.Rect(0, 0, 1280, 720),
 			TargetSize: image.Point{X: 1075, Y: 605},
+			Fit:        FitCover,
+			Adjust:     webcamAdjust(),
 		},
 	}
 }
@@ -252,31 +705,103 @@ func (m *Manager) GetCropAssets() []Asset {
 // Matches lines 247-263 of the bash script
 func (m *Manager) GetCompositeLayout() []CompositeLayer {
 	return []CompositeLayer{
-		{ImagePath: filepath.Join(m.AssetsDir, "background_s.jpg"), Position: image.Point{X: 0, Y: 0}},
-		{ImagePath: filepath.Join(m.AssetsDir, "weather_gov_hourly_forecast_s.jpg"), Position: image.Point{X: 20, Y: 1130}},
-		{ImagePath: filepath.Join(m.AssetsDir, "weather_gov_extended_forecast_s.jpg"), Position: image.Point{X: 2680, Y: 1810}},
-		{ImagePath: filepath.Join(m.AssetsDir, "nwac_avalanche_forcast_s.jpg"), Position: image.Point{X: 3420, Y: 420}},
-		{ImagePath: filepath.Join(m.AssetsDir, "nwac_stevens_observations_s.jpg"), Position: image.Point{X: 20, Y: 20}},
-		{ImagePath: filepath.Join(m.AssetsDir, "wsdot_us2_skykomish.jpg"), Position: image.Point{X: 900, Y: 20}},
-		{ImagePath: filepath.Join(m.AssetsDir, "wsdot_w_stevens.jpg"), Position: image.Point{X: 1250, Y: 20}},
-		{ImagePath: filepath.Join(m.AssetsDir, "wsdot_big_windy.jpg"), Position: image.Point{X: 1600, Y: 20}},
-		{ImagePath: filepath.Join(m.AssetsDir, "wsdot_stevens_pass_b.jpg"), Position: image.Point{X: 1950, Y: 20}},
-		{ImagePath: filepath.Join(m.AssetsDir, "wsdot_e_stevens_summit.jpg"), Position: image.Point{X: 2360, Y: 20}},
-		{ImagePath: filepath.Join(m.AssetsDir, "stevenspassjupiter_s.jpg"), Position: image.Point{X: 905, Y: 285}},
-		{ImagePath: filepath.Join(m.AssetsDir, "stevenspassskyline_s.jpg"), Position: image.Point{X: 905, Y: 920}},
-		{ImagePath: filepath.Join(m.AssetsDir, "stevenspassschool_s.jpg"), Position: image.Point{X: 905, Y: 1555}},
-		{ImagePath: filepath.Join(m.AssetsDir, "stevenspasssnowstake_s.jpg"), Position: image.Point{X: 2010, Y: 285}},
-		{ImagePath: filepath.Join(m.AssetsDir, "stevenspasscourtyard_s.jpg"), Position: image.Point{X: 2010, Y: 697}},
+		{ImagePath: filepath.Join(m.AssetsDir, "background_s.jpg"), Position: image.Point{X: 0, Y: 0}, Backdrop: &Backdrop{Blur: 6, Dim: 0.3, Padding: 12, Vignette: 0.25}},
+		{ImagePath: filepath.Join(m.AssetsDir, "weather_gov_hourly_forecast_s.png"), Position: image.Point{X: 20, Y: 1130}, Size: image.Point{X: 855, Y: 930}, Style: panelStyle()},
+		{ImagePath: filepath.Join(m.AssetsDir, "weather_gov_extended_forecast_s.png"), Position: image.Point{X: 2680, Y: 1810}, Style: panelStyle()},
+		{ImagePath: filepath.Join(m.AssetsDir, "nwac_avalanche_forcast_s.png"), Position: image.Point{X: 3420, Y: 420}, Style: panelStyle()},
+		{ImagePath: filepath.Join(m.AssetsDir, "nwac_stevens_observations_s.png"), Position: image.Point{X: 20, Y: 20}, Style: panelStyle()},
+		{ImagePath: filepath.Join(m.AssetsDir, "wsdot_us2_skykomish.jpg"), Position: image.Point{X: 900, Y: 20}, Style: panelStyle(), Caption: m.cameraCaption("wsdot_us2_skykomish.jpg", CaptionOutlined)},
+		{ImagePath: filepath.Join(m.AssetsDir, "wsdot_w_stevens.jpg"), Position: image.Point{X: 1250, Y: 20}, Style: panelStyle(), Caption: m.cameraCaption("wsdot_w_stevens.jpg", CaptionOutlined)},
+		{ImagePath: filepath.Join(m.AssetsDir, "wsdot_big_windy.jpg"), Position: image.Point{X: 1600, Y: 20}, Style: panelStyle(), Caption: m.cameraCaption("wsdot_big_windy.jpg", CaptionOutlined)},
+		{ImagePath: filepath.Join(m.AssetsDir, "wsdot_stevens_pass_b.jpg"), Position: image.Point{X: 1950, Y: 20}, Style: panelStyle(), Caption: m.cameraCaption("wsdot_stevens_pass_b.jpg", CaptionOutlined)},
+		{ImagePath: filepath.Join(m.AssetsDir, "wsdot_e_stevens_summit.jpg"), Position: image.Point{X: 2360, Y: 20}, Style: panelStyle(), Caption: m.cameraCaption("wsdot_e_stevens_summit.jpg", CaptionOutlined)},
+		{ImagePath: filepath.Join(m.AssetsDir, "stevenspassjupiter_s.jpg"), Position: image.Point{X: 905, Y: 285}, Night: NightLastLight, Style: panelStyle(), Caption: m.cameraCaption("stevenspassjupiter_s.jpg", CaptionBacked)},
+		{ImagePath: filepath.Join(m.AssetsDir, "stevenspassskyline_s.jpg"), Position: image.Point{X: 905, Y: 920}, Night: NightDim, Style: panelStyle(), Caption: m.cameraCaption("stevenspassskyline_s.jpg", CaptionBacked)},
+		{ImagePath: filepath.Join(m.AssetsDir, "stevenspassschool_s.jpg"), Position: image.Point{X: 905, Y: 1555}, Night: NightLastLight, Style: panelStyle(), Caption: m.cameraCaption("stevenspassschool_s.jpg", CaptionBacked)},
+		{ImagePath: filepath.Join(m.AssetsDir, "stevenspasssnowstake_s.jpg"), Position: image.Point{X: 2010, Y: 285}, Style: panelStyle(), Caption: m.cameraCaption("stevenspasssnowstake_s.jpg", CaptionBacked)},
+		{ImagePath: filepath.Join(m.AssetsDir, "stevenspasscourtyard_s.jpg"), Position: image.Point{X: 2010, Y: 697}, Style: panelStyle(), Caption: m.cameraCaption("stevenspasscourtyard_s.jpg", CaptionBacked)},
 		{ImagePath: filepath.Join(m.AssetsDir, "pass_conditions.png"), Position: image.Point{X: 3050, Y: 420}},
+		{ImagePath: filepath.Join(m.AssetsDir, "pass_strip.png"), Position: image.Point{X: 2010, Y: 1110}},
+		{ImagePath: filepath.Join(m.AssetsDir, "afd_panel.png"), Position: m.GetAFDPanel().Box.Min},
 		{ImagePath: filepath.Join(m.AssetsDir, "nwac_stevens_avalanche_forcast.png"), Position: image.Point{X: 3100, Y: 60}},
 	}
 }
 
+// GetRenderEncoding returns the encoding for the rendered composite
+func (m *Manager) GetRenderEncoding() Encoding {
+	return Encoding{Format: FormatJPEG, Quality: 90}
+}
+
 // GetPassConditionsImagePath returns the path for the pass conditions overlay
 func (m *Manager) GetPassConditionsImagePath() string {
 	return filepath.Join(m.AssetsDir, "pass_conditions.png")
 }
 
+// GetAFDPanel returns the Seattle Area Forecast Discussion panel configuration
+func (m *Manager) GetAFDPanel() TextPanel {
+	return TextPanel{
+		Name:      "NWS Seattle Forecast Discussion",
+		Office:    "SEW",
+		Product:   "AFD",
+		TextPath:  filepath.Join(m.AssetsDir, "afd_sew.txt"),
+		ImagePath: filepath.Join(m.AssetsDir, "afd_panel.png"),
+		Box:       image.Rect(2010, 1220, 3010, 1780),
+	}
+}
+
+// GetPassStripImagePath returns the path for the multi-pass status strip overlay
+func (m *Manager) GetPassStripImagePath() string {
+	return filepath.Join(m.AssetsDir, "pass_strip.png")
+}
+
+// GetPassStatusUnknownGraphicPath returns the graphic shown when the pass status
+// cannot be determined (e.g. the WSDOT page layout changed)
+func (m *Manager) GetPassStatusUnknownGraphicPath() string {
+	return filepath.Join(m.GraphicsDir, "hw2_unknown.png")
+}
+
+// GetRunResultsPath returns the path of the run results file
+func (m *Manager) GetRunResultsPath() string {
+	return filepath.Join(m.AssetsDir, "run_results.json")
+}
+
+// GetPassHistoryPath returns the path of the pass status history log
+// Stored in a subdirectory so flushing the assets directory keeps it
+func (m *Manager) GetPassHistoryPath() string {
+	return filepath.Join(m.AssetsDir, "history", "pass_history.json")
+}
+
+// GetScrapeDebugDir returns the directory for scrape failure evidence bundles
+// Subdirectory so flushes keep the evidence for later runs
+func (m *Manager) GetScrapeDebugDir() string {
+	return filepath.Join(m.AssetsDir, "debug")
+}
+
+// GetLocation returns the location used for sunrise/sunset (night handling of webcams)
+func (m *Manager) GetLocation() Location {
+	return Location{Name: "Stevens Pass", Latitude: 47.7448, Longitude: -121.0890}
+}
+
+// GetHUD returns the status strip configuration (nil = no strip)
+// A footer under the extended forecast panel
+func (m *Manager) GetHUD() *HUD {
+	return &HUD{
+		Region:     image.Rect(2680, 2118, 3826, 2160),
+		StaleAfter: time.Hour,
+	}
+}
+
+// GetLastLightDir returns the directory holding each webcam layer's last daylight frame
+// Subdirectory so flushes keep the frames through the night
+func (m *Manager) GetLastLightDir() string {
+	return filepath.Join(m.AssetsDir, "lastlight")
+}
+
+// GetHARDir returns the directory for scrape HAR recordings and golden screenshots
+func (m *Manager) GetHARDir() string {
+	return filepath.Join(m.AssetsDir, "har")
+}
+
 // GetPassStatusGraphicPath returns the path to the graphic based on pass status
 // Returns the appropriate graphic file based on east/west closure status:
 // - hw2_open.png = not closed
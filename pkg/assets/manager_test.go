@@ -0,0 +1,74 @@
+package assets
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+// TestCompositeLayout_CameraCaptions checks every layer against its caption, so a camera
+// renamed in the layout but not in the download targets (or the reverse) is caught here
+// rather than showing up as a missing caption on the desktop
+func TestCompositeLayout_CameraCaptions(t *testing.T) {
+	m := NewManager(t.TempDir())
+
+	// Camera layer image -> download target name
+	cameras := map[string]string{
+		"wsdot_us2_skykomish.jpg":    "WSDOT US2 Skykomish",
+		"wsdot_w_stevens.jpg":        "WSDOT W Stevens",
+		"wsdot_big_windy.jpg":        "WSDOT Big Windy",
+		"wsdot_stevens_pass_b.jpg":   "WSDOT Stevens Pass",
+		"wsdot_e_stevens_summit.jpg": "WSDOT E Stevens Summit",
+		"stevenspassjupiter_s.jpg":   "Stevens Pass Jupiter",
+		"stevenspassskyline_s.jpg":   "Stevens Pass Skyline",
+		"stevenspassschool_s.jpg":    "Stevens Pass School",
+		"stevenspasssnowstake_s.jpg": "Stevens Pass Snow Stake",
+		"stevenspasscourtyard_s.jpg": "Stevens Pass Courtyard",
+	}
+
+	found := 0
+	for _, layer := range m.GetCompositeLayout() {
+		file := filepath.Base(layer.ImagePath)
+		name, isCamera := cameras[file]
+		if !isCamera {
+			if layer.Caption != nil {
+				t.Errorf("%s: unexpected caption %q", file, layer.Caption.Text)
+			}
+			continue
+		}
+		found++
+
+		if layer.Caption == nil {
+			t.Errorf("%s: no caption (no download target produces it)", file)
+			continue
+		}
+		if layer.Caption.Text != name {
+			t.Errorf("%s: caption %q, want %q", file, layer.Caption.Text, name)
+		}
+		if layer.Caption.TimeFrom == "" {
+			t.Errorf("%s: caption has no capture time source", file)
+		}
+	}
+	if found != len(cameras) {
+		t.Errorf("camera layers in layout = %d, want %d", found, len(cameras))
+	}
+}
+
+func TestCameraCaption_ThroughCropAsset(t *testing.T) {
+	m := NewManager(t.TempDir())
+
+	caption := m.cameraCaption("stevenspassjupiter_s.jpg", CaptionBacked)
+	if caption == nil {
+		t.Fatal("cameraCaption() = nil, want a caption")
+	}
+	if caption.Text != "Stevens Pass Jupiter" {
+		t.Errorf("Text = %q, want the download target name", caption.Text)
+	}
+	if want := filepath.Join(m.AssetsDir, "stevenspassjupiter.jpg"); caption.TimeFrom != want {
+		t.Errorf("TimeFrom = %q, want the downloaded file %q", caption.TimeFrom, want)
+	}
+
+	// An unknown image drops the caption instead of stopping the render
+	if caption := m.cameraCaption("no_such_cam.jpg", CaptionBacked); caption != nil {
+		t.Errorf("cameraCaption() of an unknown image = %+v, want nil", *caption)
+	}
+}
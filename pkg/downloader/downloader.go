@@ -3,6 +3,7 @@ package downloader
 import (
 	"crypto/tls"
 	"crypto/x509"
+	"encoding/json"
 	"fmt"
 	"image"
 	"image/color"
@@ -16,6 +17,7 @@ import (
 	"time"
 
 	"github.com/trodemaster/weatherdesktop/pkg/assets"
+	"github.com/trodemaster/weatherdesktop/pkg/results"
 )
 
 // Downloader handles HTTP downloads with retry logic
@@ -81,16 +83,19 @@ func (d *Downloader) DownloadAll() error {
 
 	var wg sync.WaitGroup
 	errorsChan := make(chan error, len(downloadTargets))
+	outcomes := make([]results.Outcome, len(downloadTargets))
 
-	for _, target := range downloadTargets {
+	for i, target := range downloadTargets {
 		wg.Add(1)
-		go func(t assets.DownloadTarget) {
+		go func(i int, t assets.DownloadTarget) {
 			defer wg.Done()
 
 			log.Printf("Downloading %s from %s", t.Name, t.URL)
+			outcomes[i] = results.Outcome{Name: t.Name, Path: t.OutputPath}
 
 			// For GOES18, save backup on successful download
 			if err := d.downloadWithRetry(t.URL, t.OutputPath, 3); err != nil {
+				outcomes[i].Err = err
 				log.Printf("Failed to download %s: %v, creating fallback image", t.Name, err)
 				if err := d.createFallbackImage(t.OutputPath); err != nil {
 					errorsChan <- fmt.Errorf("failed to create fallback for %s: %w", t.Name, err)
@@ -102,12 +107,17 @@ func (d *Downloader) DownloadAll() error {
 					log.Printf("Warning: Failed to save backup for %s: %v", t.Name, err)
 				}
 			}
-		}(target)
+		}(i, target)
 	}
 	
 	wg.Wait()
 	close(errorsChan)
 	
+	// Record each download for the render HUD; a download's capture time is its HTTP Last-Modified
+	if err := results.RecordSources(d.manager.GetRunResultsPath(), outcomes...); err != nil {
+		log.Printf("Warning: %v", err)
+	}
+	
 	// Collect any errors
 	var errors []error
 	for err := range errorsChan {
@@ -164,13 +174,23 @@ func (d *Downloader) download(url, destPath string) error {
 	if err != nil {
 		return fmt.Errorf("failed to create file: %w", err)
 	}
-	defer out.Close()
 	
 	// Copy response body to file
 	_, err = io.Copy(out, resp.Body)
 	if err != nil {
+		out.Close()
 		return fmt.Errorf("failed to write file: %w", err)
 	}
+	if err := out.Close(); err != nil {
+		return fmt.Errorf("failed to close file: %w", err)
+	}
+	
+	// Keep the server's capture time (captions read it); without it the file has the fetch time
+	if modified, err := http.ParseTime(resp.Header.Get("Last-Modified")); err == nil {
+		if err := os.Chtimes(destPath, modified, modified); err != nil {
+			log.Printf("Warning: Failed to set modification time of %s: %v", destPath, err)
+		}
+	}
 	
 	return nil
 }
@@ -242,3 +262,73 @@ func (d *Downloader) copyFile(src, dst string) error {
 	return nil
 }
 
+
+// nwsAPIBase is the api.weather.gov endpoint for text products
+const nwsAPIBase = "https://api.weather.gov"
+
+// nwsUserAgent identifies us to api.weather.gov (requests without a User-Agent are rejected)
+const nwsUserAgent = "(weatherdesktop, github.com/trodemaster/weatherdesktop)"
+
+// DownloadTextProduct fetches the latest NWS text product for a panel and saves its text
+func (d *Downloader) DownloadTextProduct(panel assets.TextPanel) error {
+	log.Printf("Downloading %s (%s%s)", panel.Name, panel.Product, panel.Office)
+
+	// Step 1: List products of this type for the office (newest first)
+	var list struct {
+		Graph []struct {
+			ID           string `json:"id"`
+			IssuanceTime string `json:"issuanceTime"`
+		} `json:"@graph"`
+	}
+	listURL := fmt.Sprintf("%s/products/types/%s/locations/%s", nwsAPIBase, panel.Product, panel.Office)
+	if err := d.getJSON(listURL, &list); err != nil {
+		return fmt.Errorf("failed to list %s products: %w", panel.Product, err)
+	}
+	if len(list.Graph) == 0 {
+		return fmt.Errorf("no %s products found for %s", panel.Product, panel.Office)
+	}
+
+	// Step 2: Fetch the latest product text
+	var product struct {
+		ProductText string `json:"productText"`
+	}
+	if err := d.getJSON(nwsAPIBase+"/products/"+list.Graph[0].ID, &product); err != nil {
+		return fmt.Errorf("failed to fetch %s product: %w", panel.Product, err)
+	}
+	if product.ProductText == "" {
+		return fmt.Errorf("%s product %s has no text", panel.Product, list.Graph[0].ID)
+	}
+
+	if err := os.WriteFile(panel.TextPath, []byte(product.ProductText), 0644); err != nil {
+		return fmt.Errorf("failed to write product text: %w", err)
+	}
+
+	log.Printf("Saved %s issued %s to %s", panel.Product, list.Graph[0].IssuanceTime, panel.TextPath)
+	return nil
+}
+
+// getJSON performs a GET request against api.weather.gov and decodes the JSON response
+func (d *Downloader) getJSON(url string, v interface{}) error {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("User-Agent", nwsUserAgent)
+	req.Header.Set("Accept", "application/ld+json")
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,190 @@
+package history
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/trodemaster/weatherdesktop/pkg/parser"
+)
+
+// Limits keep the JSON log small (one record per run, ~6 weeks at 30 minute intervals)
+const (
+	maxRecords = 2000
+	maxEvents  = 500
+)
+
+// EventType describes a pass status transition
+type EventType string
+
+const (
+	EventOpened             EventType = "opened"
+	EventClosed             EventType = "closed"
+	EventRestrictionChanged EventType = "restriction_changed"
+)
+
+// Record is a single parsed pass status
+type Record struct {
+	Time     time.Time `json:"time"`
+	East     string    `json:"east"`
+	West     string    `json:"west"`
+	IsClosed bool      `json:"is_closed"`
+}
+
+// Event is a transition between two consecutive records for one direction
+type Event struct {
+	Time      time.Time `json:"time"`
+	Type      EventType `json:"type"`
+	Direction string    `json:"direction"` // "East" or "West"
+	From      string    `json:"from"`
+	To        string    `json:"to"`
+}
+
+// Store is a persistent pass status history backed by a JSON file
+type Store struct {
+	Records []Record `json:"records"`
+	Events  []Event  `json:"events"`
+
+	path string
+}
+
+// Load reads the history store from path, returning an empty store if the file does not exist
+func Load(path string) (*Store, error) {
+	s := &Store{path: path}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return s, fmt.Errorf("failed to read pass history: %w", err)
+	}
+
+	if err := json.Unmarshal(data, s); err != nil {
+		return s, fmt.Errorf("failed to parse pass history: %w", err)
+	}
+
+	return s, nil
+}
+
+// Save writes the history store back to the file it was loaded from
+func (s *Store) Save() error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode pass history: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return fmt.Errorf("failed to create pass history directory: %w", err)
+	}
+
+	if err := os.WriteFile(s.path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write pass history: %w", err)
+	}
+
+	return nil
+}
+
+// Record appends a parsed status and returns the transition events it caused
+// The first record in an empty store produces no events
+func (s *Store) Record(status *parser.PassStatus, t time.Time) []Event {
+	rec := Record{
+		Time:     t,
+		East:     status.East,
+		West:     status.West,
+		IsClosed: status.IsClosed,
+	}
+
+	var events []Event
+	if prev, ok := s.Last(); ok {
+		events = append(events, transition(t, "East", prev.East, rec.East)...)
+		events = append(events, transition(t, "West", prev.West, rec.West)...)
+	}
+
+	s.Records = append(s.Records, rec)
+	s.Events = append(s.Events, events...)
+
+	if len(s.Records) > maxRecords {
+		s.Records = s.Records[len(s.Records)-maxRecords:]
+	}
+	if len(s.Events) > maxEvents {
+		s.Events = s.Events[len(s.Events)-maxEvents:]
+	}
+
+	return events
+}
+
+// Last returns the most recent record
+func (s *Store) Last() (Record, bool) {
+	if len(s.Records) == 0 {
+		return Record{}, false
+	}
+	return s.Records[len(s.Records)-1], true
+}
+
+// RecentEvents returns up to n of the most recent events, newest first
+func (s *Store) RecentEvents(n int) []Event {
+	if n <= 0 || n > len(s.Events) {
+		n = len(s.Events)
+	}
+
+	events := make([]Event, 0, n)
+	for i := len(s.Events) - 1; i >= len(s.Events)-n; i-- {
+		events = append(events, s.Events[i])
+	}
+	return events
+}
+
+// ClosedSince returns the start of the current closure
+// Walks back through consecutive closed records; if the closure started before
+// the oldest record, the oldest record time is returned (a lower bound)
+func (s *Store) ClosedSince() (time.Time, bool) {
+	var since time.Time
+	for i := len(s.Records) - 1; i >= 0; i-- {
+		if !s.Records[i].IsClosed {
+			break
+		}
+		since = s.Records[i].Time
+	}
+	return since, !since.IsZero()
+}
+
+// transition compares one direction's previous and current status
+func transition(t time.Time, direction, from, to string) []Event {
+	if from == to {
+		return nil
+	}
+
+	eventType := EventRestrictionChanged
+	wasClosed, nowClosed := parser.IsDirectionClosed(from), parser.IsDirectionClosed(to)
+	if !wasClosed && nowClosed {
+		eventType = EventClosed
+	} else if wasClosed && !nowClosed {
+		eventType = EventOpened
+	}
+
+	return []Event{{
+		Time:      t,
+		Type:      eventType,
+		Direction: direction,
+		From:      from,
+		To:        to,
+	}}
+}
+
+// FormatDuration formats a duration like "3h 20m" (or "45m" under an hour)
+func FormatDuration(d time.Duration) string {
+	d = d.Round(time.Minute)
+	hours := int(d.Hours())
+	minutes := int(d.Minutes()) % 60
+
+	if hours >= 24 {
+		return fmt.Sprintf("%dd %dh", hours/24, hours%24)
+	}
+	if hours > 0 {
+		return fmt.Sprintf("%dh %dm", hours, minutes)
+	}
+	return fmt.Sprintf("%dm", minutes)
+}
@@ -0,0 +1,86 @@
+package history
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/trodemaster/weatherdesktop/pkg/parser"
+)
+
+const chains = "Traction Tires Required, Chains required on Vehicles over 10,000 gross vehicle weight."
+
+func TestRecord_Transitions(t *testing.T) {
+	s := &Store{}
+	base := time.Date(2024, 1, 9, 12, 0, 0, 0, time.UTC)
+
+	if events := s.Record(&parser.PassStatus{East: "No restrictions", West: "No restrictions"}, base); len(events) != 0 {
+		t.Fatalf("Expected no events for first record, got %v", events)
+	}
+
+	events := s.Record(&parser.PassStatus{East: chains, West: "No restrictions"}, base.Add(time.Hour))
+	if len(events) != 1 || events[0].Type != EventRestrictionChanged || events[0].Direction != "East" {
+		t.Errorf("Expected East restriction_changed, got %+v", events)
+	}
+
+	events = s.Record(&parser.PassStatus{East: "Pass Closed", West: "Pass Closed", IsClosed: true}, base.Add(2*time.Hour))
+	if len(events) != 2 || events[0].Type != EventClosed || events[1].Type != EventClosed {
+		t.Errorf("Expected two closed events, got %+v", events)
+	}
+
+	events = s.Record(&parser.PassStatus{East: "Pass Closed", West: chains, IsClosed: true}, base.Add(3*time.Hour))
+	if len(events) != 1 || events[0].Type != EventOpened || events[0].Direction != "West" {
+		t.Errorf("Expected West opened, got %+v", events)
+	}
+
+	recent := s.RecentEvents(2)
+	if len(recent) != 2 || recent[0].Type != EventOpened {
+		t.Errorf("Expected newest event first, got %+v", recent)
+	}
+
+	t.Logf("✓ Transitions detected correctly (%d events)", len(s.Events))
+}
+
+func TestClosedSince(t *testing.T) {
+	s := &Store{}
+	base := time.Date(2024, 1, 9, 12, 0, 0, 0, time.UTC)
+
+	if _, ok := s.ClosedSince(); ok {
+		t.Error("Expected no closure for empty store")
+	}
+
+	s.Record(&parser.PassStatus{East: "No restrictions", West: "No restrictions"}, base)
+	s.Record(&parser.PassStatus{East: "Pass Closed", West: "Pass Closed", IsClosed: true}, base.Add(30*time.Minute))
+	s.Record(&parser.PassStatus{East: "Pass Closed", West: "Pass Closed", IsClosed: true}, base.Add(4*time.Hour))
+
+	since, ok := s.ClosedSince()
+	if !ok || !since.Equal(base.Add(30*time.Minute)) {
+		t.Errorf("Expected closed since %v, got %v (ok=%v)", base.Add(30*time.Minute), since, ok)
+	}
+
+	if got := FormatDuration(base.Add(3*time.Hour + 50*time.Minute).Sub(since)); got != "3h 20m" {
+		t.Errorf("Expected '3h 20m', got '%s'", got)
+	}
+}
+
+func TestLoadSave(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pass_history.json")
+
+	s, err := Load(path)
+	if err != nil {
+		t.Fatalf("Expected no error loading missing file, got %v", err)
+	}
+	s.Record(&parser.PassStatus{East: "No restrictions", West: "No restrictions"}, time.Now())
+	s.Record(&parser.PassStatus{East: "Pass Closed", West: "Pass Closed", IsClosed: true}, time.Now())
+	if err := s.Save(); err != nil {
+		t.Fatalf("Expected no error saving, got %v", err)
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Expected no error loading, got %v", err)
+	}
+	if len(loaded.Records) != 2 || len(loaded.Events) != 2 {
+		t.Errorf("Expected 2 records and 2 events, got %d and %d", len(loaded.Records), len(loaded.Events))
+	}
+}
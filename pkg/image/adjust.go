@@ -0,0 +1,184 @@
+package image
+
+import (
+	"fmt"
+	"image"
+	"math"
+
+	"github.com/trodemaster/weatherdesktop/pkg/assets"
+	"golang.org/x/image/draw"
+)
+
+// Adjustment defaults
+const (
+	defaultLevelsClip    = 0.005 // Share of darkest/brightest pixels ignored by levels and stretch
+	minLevelsRange       = 32    // Narrower ranges (night frames, solid fog) are left alone instead of amplifying noise
+	defaultUnsharpRadius = 1.0
+)
+
+// adjust applies an asset's adjustment steps in order
+// Steps work on straight (non-premultiplied) color; alpha is left unchanged
+func (p *Processor) adjust(img image.Image, steps []assets.Adjustment) (image.Image, error) {
+	if len(steps) == 0 {
+		return img, nil
+	}
+
+	// Copy to NRGBA with the origin at (0, 0) (img may be a sub-image)
+	b := img.Bounds()
+	dst := image.NewNRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(dst, dst.Bounds(), img, b.Min, draw.Src)
+
+	for _, step := range steps {
+		switch step.Kind {
+		case assets.AdjustAutoLevels:
+			applyLevels(dst, levelsLUTs(dst, clipShare(step), false))
+		case assets.AdjustStretch:
+			applyLevels(dst, levelsLUTs(dst, clipShare(step), true))
+		case assets.AdjustGamma:
+			if step.Amount <= 0 {
+				return nil, fmt.Errorf("gamma must be positive, got %v", step.Amount)
+			}
+			lut := curveLUT(func(v float64) float64 { return math.Pow(v, 1/step.Amount) })
+			applyLevels(dst, [3][256]uint8{lut, lut, lut})
+		case assets.AdjustBrightnessContrast:
+			lut := curveLUT(func(v float64) float64 {
+				return (v-0.5)*(1+step.Contrast) + 0.5 + step.Brightness
+			})
+			applyLevels(dst, [3][256]uint8{lut, lut, lut})
+		case assets.AdjustSaturation:
+			saturate(dst, step.Amount)
+		case assets.AdjustUnsharp:
+			radius := step.Radius
+			if radius <= 0 {
+				radius = defaultUnsharpRadius
+			}
+			dst = unsharp(dst, radius, step.Amount, step.Threshold)
+		default:
+			return nil, fmt.Errorf("unknown adjustment %q", step.Kind)
+		}
+	}
+
+	return dst, nil
+}
+
+// clipShare returns the step's levels clip share or the default
+func clipShare(step assets.Adjustment) float64 {
+	if step.Clip <= 0 {
+		return defaultLevelsClip
+	}
+	return step.Clip
+}
+
+// curveLUT tabulates f over 0-1 into a 256-entry lookup table
+func curveLUT(f func(float64) float64) [256]uint8 {
+	var lut [256]uint8
+	for i := range lut {
+		lut[i] = clampByte(int32(math.Round(f(float64(i)/255) * 255)))
+	}
+	return lut
+}
+
+// levelsLUTs returns per-channel curves mapping the clipped low/high ends to 0 and 255
+// With luma set, all channels share the curve computed from luminance (no hue shift)
+func levelsLUTs(img *image.NRGBA, clip float64, luma bool) [3][256]uint8 {
+	var hist [3][256]int
+	n := 0
+	for i := 0; i < len(img.Pix); i += 4 {
+		if img.Pix[i+3] == 0 {
+			continue // Fully transparent pixels carry no color
+		}
+		r, g, b := img.Pix[i], img.Pix[i+1], img.Pix[i+2]
+		if luma {
+			hist[0][lumaOf(r, g, b)]++
+		} else {
+			hist[0][r]++
+			hist[1][g]++
+			hist[2][b]++
+		}
+		n++
+	}
+
+	var luts [3][256]uint8
+	for c := 0; c < 3; c++ {
+		src := c
+		if luma {
+			src = 0
+		}
+		lo, hi := histRange(hist[src], n, clip)
+		if hi-lo < minLevelsRange {
+			luts[c] = curveLUT(func(v float64) float64 { return v })
+			continue
+		}
+		luts[c] = curveLUT(func(v float64) float64 {
+			return (v*255 - float64(lo)) / float64(hi-lo)
+		})
+	}
+	return luts
+}
+
+// histRange returns the levels below and above which clip of the n pixels fall
+func histRange(hist [256]int, n int, clip float64) (lo, hi int) {
+	limit := int(float64(n) * clip)
+
+	count := 0
+	for lo = 0; lo < 255; lo++ {
+		count += hist[lo]
+		if count > limit {
+			break
+		}
+	}
+	count = 0
+	for hi = 255; hi > 0; hi-- {
+		count += hist[hi]
+		if count > limit {
+			break
+		}
+	}
+	return lo, hi
+}
+
+// applyLevels maps each color channel through its lookup table
+func applyLevels(img *image.NRGBA, luts [3][256]uint8) {
+	for i := 0; i < len(img.Pix); i += 4 {
+		img.Pix[i] = luts[0][img.Pix[i]]
+		img.Pix[i+1] = luts[1][img.Pix[i+1]]
+		img.Pix[i+2] = luts[2][img.Pix[i+2]]
+	}
+}
+
+// saturate scales each pixel's distance from its gray level by factor
+func saturate(img *image.NRGBA, factor float64) {
+	for i := 0; i < len(img.Pix); i += 4 {
+		l := float64(lumaOf(img.Pix[i], img.Pix[i+1], img.Pix[i+2]))
+		for c := 0; c < 3; c++ {
+			v := l + (float64(img.Pix[i+c])-l)*factor
+			img.Pix[i+c] = clampByte(int32(math.Round(v)))
+		}
+	}
+}
+
+// unsharp adds amount times the difference from a Gaussian blur wherever it exceeds threshold
+func unsharp(img *image.NRGBA, radius, amount float64, threshold int) *image.NRGBA {
+	blurred := gaussianBlur(img, radius)
+	dst := image.NewNRGBA(img.Rect)
+
+	for i := 0; i < len(img.Pix); i += 4 {
+		for c := 0; c < 3; c++ {
+			orig := int32(img.Pix[i+c])
+			diff := orig - int32(blurred.Pix[i+c])
+			if diff < int32(threshold) && -diff < int32(threshold) {
+				dst.Pix[i+c] = img.Pix[i+c]
+				continue
+			}
+			dst.Pix[i+c] = clampByte(orig + int32(math.Round(amount*float64(diff))))
+		}
+		dst.Pix[i+3] = img.Pix[i+3]
+	}
+
+	return dst
+}
+
+// lumaOf returns the Rec. 601 luminance of a color (0-255)
+func lumaOf(r, g, b uint8) uint8 {
+	return uint8((299*int(r) + 587*int(g) + 114*int(b) + 500) / 1000)
+}
@@ -0,0 +1,130 @@
+package image
+
+import (
+	"flag"
+	"image"
+	"image/color"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/trodemaster/weatherdesktop/pkg/assets"
+)
+
+var updateGolden = flag.Bool("update", false, "rewrite golden images in testfiles/golden")
+
+// goldenPath returns a golden image path under testfiles/golden
+func goldenPath(name string) string {
+	_, file, _, _ := runtime.Caller(0)
+	projectRoot := filepath.Join(filepath.Dir(file), "..", "..")
+	return filepath.Join(projectRoot, "testfiles", "golden", name+".png")
+}
+
+// checkGolden compares img with its golden image, allowing 1 level per channel
+// With -update the golden is rewritten instead
+func checkGolden(t *testing.T, name string, img image.Image) {
+	t.Helper()
+	path := goldenPath(name)
+
+	if *updateGolden {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		writeTestPNG(t, path, img)
+		return
+	}
+
+	golden, err := LoadImageForComposite(path)
+	if err != nil {
+		t.Fatalf("failed to load golden (run with -update to create it): %v", err)
+	}
+	if golden.Bounds().Size() != img.Bounds().Size() {
+		t.Fatalf("size = %v, golden %v", img.Bounds().Size(), golden.Bounds().Size())
+	}
+
+	gb, ib := golden.Bounds(), img.Bounds()
+	for y := 0; y < gb.Dy(); y++ {
+		for x := 0; x < gb.Dx(); x++ {
+			want := color.NRGBAModel.Convert(golden.At(gb.Min.X+x, gb.Min.Y+y)).(color.NRGBA)
+			got := color.NRGBAModel.Convert(img.At(ib.Min.X+x, ib.Min.Y+y)).(color.NRGBA)
+			if absDiff(got.R, want.R) > 1 || absDiff(got.G, want.G) > 1 || absDiff(got.B, want.B) > 1 || got.A != want.A {
+				t.Fatalf("pixel (%d, %d) = %v, golden %v", x, y, got, want)
+			}
+		}
+	}
+}
+
+// foggyFrame returns a low-contrast, blue-tinted scene: sky gradient, dark ridge, and a bright sign
+func foggyFrame() *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, 96, 64))
+	for y := 0; y < 64; y++ {
+		for x := 0; x < 96; x++ {
+			v := uint8(150 - y/2)
+			c := color.NRGBA{v - 10, v, v + 20, 255}
+			if y > 40-x/8 { // Ridge
+				c = color.NRGBA{90, 100, 115, 255}
+			}
+			if x >= 60 && x < 72 && y >= 44 && y < 52 { // Sign
+				c = color.NRGBA{170, 150, 110, 255}
+			}
+			img.SetNRGBA(x, y, c)
+		}
+	}
+	return img
+}
+
+func TestAdjustGolden(t *testing.T) {
+	p := &Processor{}
+
+	tests := []struct {
+		name  string
+		steps []assets.Adjustment
+	}{
+		{"auto_levels", []assets.Adjustment{{Kind: assets.AdjustAutoLevels}}},
+		{"stretch", []assets.Adjustment{{Kind: assets.AdjustStretch, Clip: 0.01}}},
+		{"gamma", []assets.Adjustment{{Kind: assets.AdjustGamma, Amount: 1.8}}},
+		{"brightness_contrast", []assets.Adjustment{{Kind: assets.AdjustBrightnessContrast, Brightness: 0.1, Contrast: 0.5}}},
+		{"saturation", []assets.Adjustment{{Kind: assets.AdjustSaturation, Amount: 2}}},
+		{"unsharp", []assets.Adjustment{{Kind: assets.AdjustUnsharp, Radius: 1.5, Amount: 1, Threshold: 2}}},
+		{"webcam", []assets.Adjustment{
+			{Kind: assets.AdjustAutoLevels},
+			{Kind: assets.AdjustGamma, Amount: 1.2},
+			{Kind: assets.AdjustSaturation, Amount: 1.3},
+			{Kind: assets.AdjustUnsharp, Radius: 1, Amount: 0.6, Threshold: 3},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			img, err := p.adjust(foggyFrame(), tt.steps)
+			if err != nil {
+				t.Fatalf("adjust() error: %v", err)
+			}
+			checkGolden(t, "adjust_"+tt.name, img)
+		})
+	}
+}
+
+func TestAdjustLeavesNarrowRangeAlone(t *testing.T) {
+	// Nearly black night frame: levels would only amplify sensor noise
+	night := testCanvas(32, 32, color.RGBA{8, 8, 10, 255})
+	fill(night, image.Rect(10, 10, 20, 20), color.RGBA{20, 18, 16, 255})
+
+	img, err := (&Processor{}).adjust(night, []assets.Adjustment{{Kind: assets.AdjustAutoLevels}})
+	if err != nil {
+		t.Fatalf("adjust() error: %v", err)
+	}
+	if got := color.RGBAModel.Convert(img.At(15, 15)); got != (color.RGBA{20, 18, 16, 255}) {
+		t.Errorf("night frame changed to %v", got)
+	}
+}
+
+func TestAdjustUnknown(t *testing.T) {
+	if _, err := (&Processor{}).adjust(foggyFrame(), []assets.Adjustment{{Kind: "posterize"}}); err == nil {
+		t.Error("adjust() with unknown kind: want error")
+	}
+	if _, err := (&Processor{}).adjust(foggyFrame(), []assets.Adjustment{{Kind: assets.AdjustGamma}}); err == nil {
+		t.Error("adjust() with zero gamma: want error")
+	}
+}
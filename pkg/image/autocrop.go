@@ -0,0 +1,270 @@
+package image
+
+import (
+	"image"
+	"image/color"
+	"log"
+	"math"
+
+	"github.com/trodemaster/weatherdesktop/pkg/assets"
+)
+
+// Auto-crop defaults
+const (
+	defaultAutoCropTolerance = 24
+	templateCoarseScale      = 4  // Template search runs at 1/4 size, then refines at full size
+	templateMaxMeanDiff      = 40 // Mean gray difference (0-255) above which the template is "not found"
+)
+
+// detectCrop returns the crop rectangle for an asset with AutoCrop configured
+// Falls back to CropRect (or the whole image) when no content is found
+func (p *Processor) detectCrop(img image.Image, asset assets.Asset) image.Rectangle {
+	ac := asset.AutoCrop
+	bounds := img.Bounds()
+
+	region := asset.CropRect
+	if region.Empty() {
+		region = bounds
+	}
+
+	if ac.Template != "" {
+		region = p.anchorTemplate(img, region, asset)
+	}
+
+	region = region.Intersect(bounds)
+	if region.Empty() {
+		log.Printf("Warning: Auto-crop %s: search region %v is outside the %v image, using configured rect", asset.Name, asset.CropRect, bounds.Size())
+		return asset.CropRect
+	}
+
+	tolerance := ac.Tolerance
+	if tolerance <= 0 {
+		tolerance = defaultAutoCropTolerance
+	}
+
+	rect, ok := contentBounds(img, region, tolerance)
+	if !ok {
+		log.Printf("Warning: Auto-crop %s: no content found in %v, using it as is", asset.Name, region)
+		return region
+	}
+
+	rect = fitCropBounds(rect.Inset(-ac.Padding), ac.MinSize, ac.MaxSize, bounds)
+
+	log.Printf("Auto-crop %s: detected %v (%dx%d), configured %v", asset.Name, rect, rect.Dx(), rect.Dy(), asset.CropRect)
+	return rect
+}
+
+// anchorTemplate shifts region by how far the asset's template moved from TemplateAt
+func (p *Processor) anchorTemplate(img image.Image, region image.Rectangle, asset assets.Asset) image.Rectangle {
+	ac := asset.AutoCrop
+
+	tmpl, err := p.loadImage(ac.Template)
+	if err != nil {
+		log.Printf("Warning: Auto-crop %s: failed to load template: %v", asset.Name, err)
+		return region
+	}
+
+	pos, diff, ok := findTemplate(img, tmpl)
+	if !ok {
+		log.Printf("Warning: Auto-crop %s: template not found (best mean difference %.1f), using configured rect", asset.Name, diff)
+		return region
+	}
+
+	drift := pos.Sub(ac.TemplateAt)
+	log.Printf("Auto-crop %s: template at %v (drift %+d,%+d)", asset.Name, pos, drift.X, drift.Y)
+	return region.Add(drift)
+}
+
+// contentBounds returns the bounding box of pixels in region that differ from the background
+// The background is the most common color along the region's edges
+func contentBounds(img image.Image, region image.Rectangle, tolerance int) (image.Rectangle, bool) {
+	bg := edgeColor(img, region)
+
+	minX, minY := region.Max.X, region.Max.Y
+	maxX, maxY := region.Min.X-1, region.Min.Y-1
+	for y := region.Min.Y; y < region.Max.Y; y++ {
+		for x := region.Min.X; x < region.Max.X; x++ {
+			if !differs(img.At(x, y), bg, tolerance) {
+				continue
+			}
+			if x < minX {
+				minX = x
+			}
+			if x > maxX {
+				maxX = x
+			}
+			if y < minY {
+				minY = y
+			}
+			if y > maxY {
+				maxY = y
+			}
+		}
+	}
+
+	if maxX < minX {
+		return image.Rectangle{}, false
+	}
+	return image.Rect(minX, minY, maxX+1, maxY+1), true
+}
+
+// edgeColor returns the most common color (quantized to 5 bits per channel) along the edges of r
+func edgeColor(img image.Image, r image.Rectangle) color.RGBA {
+	counts := make(map[uint32]int)
+	add := func(x, y int) {
+		c := color.RGBAModel.Convert(img.At(x, y)).(color.RGBA)
+		counts[uint32(c.R>>3)<<16|uint32(c.G>>3)<<8|uint32(c.B>>3)]++
+	}
+	for x := r.Min.X; x < r.Max.X; x++ {
+		add(x, r.Min.Y)
+		add(x, r.Max.Y-1)
+	}
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		add(r.Min.X, y)
+		add(r.Max.X-1, y)
+	}
+
+	var key uint32
+	top := -1
+	for k, n := range counts {
+		if n > top || (n == top && k < key) {
+			key, top = k, n
+		}
+	}
+
+	// Bucket center
+	return color.RGBA{
+		R: uint8(key>>16)<<3 | 4,
+		G: uint8(key>>8)<<3 | 4,
+		B: uint8(key)<<3 | 4,
+		A: 255,
+	}
+}
+
+// differs reports whether any channel of c differs from bg by more than tolerance
+func differs(c color.Color, bg color.RGBA, tolerance int) bool {
+	rgba := color.RGBAModel.Convert(c).(color.RGBA)
+	return absDiff(rgba.R, bg.R) > tolerance || absDiff(rgba.G, bg.G) > tolerance || absDiff(rgba.B, bg.B) > tolerance
+}
+
+func absDiff(a, b uint8) int {
+	if a > b {
+		return int(a - b)
+	}
+	return int(b - a)
+}
+
+// fitCropBounds applies the minimum and maximum sizes and keeps rect inside limit
+// Smaller rects grow around their center; larger ones shrink from the bottom/right
+func fitCropBounds(rect image.Rectangle, minSize, maxSize image.Point, limit image.Rectangle) image.Rectangle {
+	if minSize.X > 0 && rect.Dx() < minSize.X {
+		grow := minSize.X - rect.Dx()
+		rect.Min.X -= grow / 2
+		rect.Max.X += grow - grow/2
+	}
+	if minSize.Y > 0 && rect.Dy() < minSize.Y {
+		grow := minSize.Y - rect.Dy()
+		rect.Min.Y -= grow / 2
+		rect.Max.Y += grow - grow/2
+	}
+
+	if maxSize.X > 0 && rect.Dx() > maxSize.X {
+		rect.Max.X = rect.Min.X + maxSize.X
+	}
+	if maxSize.Y > 0 && rect.Dy() > maxSize.Y {
+		rect.Max.Y = rect.Min.Y + maxSize.Y
+	}
+
+	// Shift back inside the image before clipping so grown rects keep their size
+	if rect.Min.X < limit.Min.X {
+		rect = rect.Add(image.Pt(limit.Min.X-rect.Min.X, 0))
+	}
+	if rect.Min.Y < limit.Min.Y {
+		rect = rect.Add(image.Pt(0, limit.Min.Y-rect.Min.Y))
+	}
+	if rect.Max.X > limit.Max.X {
+		rect = rect.Add(image.Pt(limit.Max.X-rect.Max.X, 0))
+	}
+	if rect.Max.Y > limit.Max.Y {
+		rect = rect.Add(image.Pt(0, limit.Max.Y-rect.Max.Y))
+	}
+
+	return rect.Intersect(limit)
+}
+
+// findTemplate locates tmpl in img by mean absolute gray difference
+// A coarse search at 1/templateCoarseScale size is refined at full size around the best match
+func findTemplate(img, tmpl image.Image) (image.Point, float64, bool) {
+	tb := tmpl.Bounds()
+	ib := img.Bounds()
+	if tb.Dx() > ib.Dx() || tb.Dy() > ib.Dy() || tb.Empty() {
+		return image.Point{}, math.MaxFloat64, false
+	}
+
+	// Coarse search over every position
+	scale := templateCoarseScale
+	if tb.Dx()/scale < 4 || tb.Dy()/scale < 4 {
+		scale = 1 // Template too small to downsample
+	}
+	imgSmall, tmplSmall := grayScaled(img, scale), grayScaled(tmpl, scale)
+	coarse, _ := bestMatch(imgSmall, tmplSmall, image.Rect(0, 0, imgSmall.Rect.Dx()-tmplSmall.Rect.Dx()+1, imgSmall.Rect.Dy()-tmplSmall.Rect.Dy()+1))
+
+	// Refine around the coarse match at full resolution
+	imgFull, tmplFull := grayScaled(img, 1), grayScaled(tmpl, 1)
+	center := coarse.Mul(scale)
+	search := image.Rect(center.X-scale, center.Y-scale, center.X+scale+1, center.Y+scale+1).
+		Intersect(image.Rect(0, 0, imgFull.Rect.Dx()-tmplFull.Rect.Dx()+1, imgFull.Rect.Dy()-tmplFull.Rect.Dy()+1))
+	pos, diff := bestMatch(imgFull, tmplFull, search)
+
+	return pos.Add(ib.Min), diff, diff <= templateMaxMeanDiff
+}
+
+// bestMatch returns the top-left position in search with the lowest mean absolute difference
+func bestMatch(img, tmpl *image.Gray, search image.Rectangle) (image.Point, float64) {
+	tw, th := tmpl.Rect.Dx(), tmpl.Rect.Dy()
+	best, bestSum := search.Min, math.MaxInt
+
+	for y := search.Min.Y; y < search.Max.Y; y++ {
+		for x := search.Min.X; x < search.Max.X; x++ {
+			sum := 0
+			for ty := 0; ty < th && sum < bestSum; ty++ {
+				row := img.Pix[(y+ty)*img.Stride+x:]
+				trow := tmpl.Pix[ty*tmpl.Stride:]
+				for tx := 0; tx < tw; tx++ {
+					d := int(row[tx]) - int(trow[tx])
+					if d < 0 {
+						d = -d
+					}
+					sum += d
+				}
+			}
+			if sum < bestSum {
+				best, bestSum = image.Pt(x, y), sum
+			}
+		}
+	}
+
+	return best, float64(bestSum) / float64(tw*th)
+}
+
+// grayScaled converts img to grayscale, averaging scale x scale blocks
+// The result has its origin at (0, 0)
+func grayScaled(img image.Image, scale int) *image.Gray {
+	b := img.Bounds()
+	w, h := b.Dx()/scale, b.Dy()/scale
+	dst := image.NewGray(image.Rect(0, 0, w, h))
+
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			sum := 0
+			for sy := 0; sy < scale; sy++ {
+				for sx := 0; sx < scale; sx++ {
+					sum += int(color.GrayModel.Convert(img.At(b.Min.X+x*scale+sx, b.Min.Y+y*scale+sy)).(color.Gray).Y)
+				}
+			}
+			dst.Pix[y*dst.Stride+x] = uint8(sum / (scale * scale))
+		}
+	}
+
+	return dst
+}
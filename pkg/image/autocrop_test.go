@@ -0,0 +1,81 @@
+package image
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"testing"
+
+	"github.com/trodemaster/weatherdesktop/pkg/assets"
+)
+
+// testCanvas returns a w x h image filled with bg
+func testCanvas(w, h int, bg color.Color) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.Draw(img, img.Bounds(), image.NewUniform(bg), image.Point{}, draw.Src)
+	return img
+}
+
+func fill(img *image.RGBA, r image.Rectangle, c color.Color) {
+	draw.Draw(img, r, image.NewUniform(c), image.Point{}, draw.Src)
+}
+
+func TestDetectCrop(t *testing.T) {
+	p := &Processor{}
+
+	// White page with a chart drawn at (120,80)-(420,260) and a stray header inside the search region
+	img := testCanvas(600, 400, color.White)
+	fill(img, image.Rect(120, 80, 420, 260), color.RGBA{40, 90, 200, 255})
+	fill(img, image.Rect(0, 0, 600, 20), color.Black)
+
+	// Light JPEG-like noise on the background should be ignored
+	img.Set(30, 300, color.RGBA{240, 245, 250, 255})
+
+	tests := []struct {
+		name     string
+		crop     image.Rectangle
+		autoCrop assets.AutoCrop
+		want     image.Rectangle
+	}{
+		{"content", image.Rect(0, 40, 600, 400), assets.AutoCrop{}, image.Rect(120, 80, 420, 260)},
+		{"padding", image.Rect(0, 40, 600, 400), assets.AutoCrop{Padding: 10}, image.Rect(110, 70, 430, 270)},
+		{"min size", image.Rect(0, 40, 600, 400), assets.AutoCrop{MinSize: image.Pt(400, 200)}, image.Rect(70, 70, 470, 270)},
+		{"max size", image.Rect(0, 40, 600, 400), assets.AutoCrop{MaxSize: image.Pt(200, 100)}, image.Rect(120, 80, 320, 180)},
+		{"min size clamped", image.Rect(0, 40, 600, 400), assets.AutoCrop{MinSize: image.Pt(600, 500)}, image.Rect(0, 0, 600, 400)},
+		{"nothing found", image.Rect(450, 280, 600, 400), assets.AutoCrop{}, image.Rect(450, 280, 600, 400)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ac := tt.autoCrop
+			got := p.detectCrop(img, assets.Asset{Name: tt.name, CropRect: tt.crop, AutoCrop: &ac})
+			if got != tt.want {
+				t.Errorf("detectCrop() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFindTemplate(t *testing.T) {
+	// A "logo" with some internal structure so the match is unique
+	logo := testCanvas(40, 24, color.RGBA{200, 30, 30, 255})
+	fill(logo, image.Rect(6, 6, 18, 18), color.White)
+	fill(logo, image.Rect(24, 4, 34, 20), color.Black)
+
+	for _, at := range []image.Point{{0, 0}, {137, 58}, {259, 175}} {
+		img := testCanvas(300, 200, color.RGBA{230, 230, 230, 255})
+		fill(img, image.Rect(50, 120, 250, 140), color.RGBA{90, 90, 90, 255})
+		draw.Draw(img, logo.Bounds().Add(at), logo, image.Point{}, draw.Src)
+
+		pos, diff, ok := findTemplate(img, logo)
+		if !ok || pos != at {
+			t.Errorf("findTemplate() at %v = %v (diff %.1f, ok %v)", at, pos, diff, ok)
+		}
+	}
+
+	// Absent template
+	img := testCanvas(300, 200, color.RGBA{230, 230, 230, 255})
+	if pos, diff, ok := findTemplate(img, logo); ok {
+		t.Errorf("findTemplate() on empty image = %v (diff %.1f), want not found", pos, diff)
+	}
+}
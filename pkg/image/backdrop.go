@@ -0,0 +1,167 @@
+package image
+
+import (
+	"image"
+	"image/draw"
+	"log"
+	"math"
+	"os"
+	"time"
+
+	"github.com/trodemaster/weatherdesktop/pkg/assets"
+)
+
+// vignetteStart is the normalized distance from the center (1 = corner) where the vignette begins
+const vignetteStart = 0.35
+
+// applyBackdrop blurs and dims the canvas behind the layers above a backdrop layer
+func (c *Compositor) applyBackdrop(canvas *image.RGBA, bd assets.Backdrop, above []assets.CompositeLayer) {
+	start := time.Now()
+
+	regions := []image.Rectangle{canvas.Bounds()}
+	if bd.Scope != assets.BackdropGlobal {
+		regions = c.layerRegions(above, bd.Padding, canvas.Bounds())
+	}
+
+	if bd.Blur > 0 && len(regions) > 0 {
+		blurRegions(canvas, regions, bd.Blur)
+	}
+	if bd.Dim > 0 {
+		dimMasked(canvas, regionMask(canvas.Bounds(), regions), 1-bd.Dim)
+	}
+	if bd.Vignette > 0 {
+		vignette(canvas, bd.Vignette)
+	}
+
+	log.Printf("Backdrop: blur %.0f, dim %.0f%%, vignette %.0f%% over %d region(s) in %v",
+		bd.Blur, bd.Dim*100, bd.Vignette*100, len(regions), time.Since(start).Round(time.Millisecond))
+}
+
+// layerRegions returns the canvas area each layer will draw, including its shadow and plate, grown by padding
+// Layers that won't be drawn (no image on disk, or hidden for the night) are left out
+func (c *Compositor) layerRegions(layers []assets.CompositeLayer, padding int, canvas image.Rectangle) []image.Rectangle {
+	var regions []image.Rectangle
+	for _, layer := range layers {
+		cfg, err := decodeConfig(layer.ImagePath)
+		if err != nil || c.hiddenAtNight(layer) {
+			continue
+		}
+		size := layer.Size
+		if size == (image.Point{}) {
+			size = image.Pt(cfg.Width, cfg.Height)
+		}
+
+		r := image.Rectangle{Min: layer.Position, Max: layer.Position.Add(size)}
+		if layer.Style != nil {
+			r = styledBounds(r, *layer.Style)
+		}
+		if r = r.Inset(-padding).Intersect(canvas); !r.Empty() {
+			regions = append(regions, r)
+		}
+	}
+	return regions
+}
+
+// hiddenAtNight reports whether a NightHide layer's current frame is dark enough to be left out
+// Matches the check in nightFrame without saving last light frames
+func (c *Compositor) hiddenAtNight(layer assets.CompositeLayer) bool {
+	if layer.Night != assets.NightHide || c.daylight {
+		return false
+	}
+	img, err := LoadImageForComposite(layer.ImagePath)
+	if err != nil {
+		return true // compositeLayer won't draw it either
+	}
+	return meanLuma(img) < nightLuminance
+}
+
+// decodeConfig reads an image file's dimensions without decoding it
+func decodeConfig(path string) (image.Config, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return image.Config{}, err
+	}
+	defer f.Close()
+
+	cfg, _, err := image.DecodeConfig(f)
+	return cfg, err
+}
+
+// regionMask marks the pixels covered by any region, so overlaps are treated once
+func regionMask(bounds image.Rectangle, regions []image.Rectangle) *image.Alpha {
+	mask := image.NewAlpha(bounds)
+	for _, r := range regions {
+		draw.Draw(mask, r, image.Opaque, image.Point{}, draw.Src)
+	}
+	return mask
+}
+
+// blurRegions blurs the canvas inside regions
+// The blur reads 3 sigma past each region so its edges sample the real neighbours
+func blurRegions(canvas *image.RGBA, regions []image.Rectangle, sigma float64) {
+	margin := int(math.Ceil(3 * sigma))
+	var area image.Rectangle
+	for _, r := range regions {
+		area = area.Union(r.Inset(-margin))
+	}
+	area = area.Intersect(canvas.Bounds())
+
+	blurred := image.NewRGBA(area)
+	draw.Draw(blurred, area, canvas, area.Min, draw.Src)
+	boxBlurWorkers(blurred.Pix, area.Dx(), area.Dy(), blurred.Stride, 4, sigma, defaultWorkers())
+
+	for _, r := range regions {
+		draw.Draw(canvas, r, blurred, r.Min, draw.Src)
+	}
+}
+
+// dimMasked scales the color of the pixels set in mask by factor
+func dimMasked(img *image.RGBA, mask *image.Alpha, factor float64) {
+	scale := uint32(math.Round(factor * 256))
+	b := img.Rect.Intersect(mask.Rect)
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		row := img.Pix[img.PixOffset(b.Min.X, y):]
+		mrow := mask.Pix[mask.PixOffset(b.Min.X, y):]
+		for x := 0; x < b.Dx(); x++ {
+			if mrow[x] == 0 {
+				continue
+			}
+			i := x * 4
+			row[i] = uint8(uint32(row[i]) * scale >> 8)
+			row[i+1] = uint8(uint32(row[i+1]) * scale >> 8)
+			row[i+2] = uint8(uint32(row[i+2]) * scale >> 8)
+		}
+	}
+}
+
+// vignette darkens img toward its corners by up to strength
+// The falloff is a smoothstep from vignetteStart to the corners of the image's ellipse
+func vignette(img *image.RGBA, strength float64) {
+	b := img.Rect
+	cx, cy := float64(b.Min.X+b.Max.X)/2, float64(b.Min.Y+b.Max.Y)/2
+	hw, hh := float64(b.Dx())/2, float64(b.Dy())/2
+
+	// Squared normalized offsets per column and row; d² = (dx² + dy²) / 2 reaches 1 at the corners
+	dx2 := make([]float64, b.Dx())
+	for x := range dx2 {
+		d := (float64(b.Min.X+x) + 0.5 - cx) / hw
+		dx2[x] = d * d
+	}
+
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		dy := (float64(y) + 0.5 - cy) / hh
+		row := img.Pix[img.PixOffset(b.Min.X, y):]
+		for x, dxx := range dx2 {
+			d := math.Sqrt((dxx + dy*dy) / 2)
+			if d <= vignetteStart {
+				continue
+			}
+			t := min((d-vignetteStart)/(1-vignetteStart), 1)
+			scale := uint32(math.Round((1 - strength*t*t*(3-2*t)) * 256))
+			i := x * 4
+			row[i] = uint8(uint32(row[i]) * scale >> 8)
+			row[i+1] = uint8(uint32(row[i+1]) * scale >> 8)
+			row[i+2] = uint8(uint32(row[i+2]) * scale >> 8)
+		}
+	}
+}
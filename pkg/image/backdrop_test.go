@@ -0,0 +1,160 @@
+package image
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"path/filepath"
+	"testing"
+
+	"github.com/trodemaster/weatherdesktop/pkg/assets"
+)
+
+func TestLayerRegions(t *testing.T) {
+	dir := t.TempDir()
+	panel := filepath.Join(dir, "panel.png")
+	writeTestPNG(t, panel, testCanvas(40, 30, color.White))
+	dark := filepath.Join(dir, "dark.png")
+	writeTestPNG(t, dark, testCanvas(40, 30, color.RGBA{10, 10, 10, 255}))
+
+	styled := &assets.LayerStyle{
+		Shadow: &assets.Shadow{Offset: image.Pt(6, 8), Blur: 8}, // 24px blur extent
+		Plate:  &assets.Plate{Padding: 10},
+	}
+	layers := []assets.CompositeLayer{
+		{ImagePath: panel, Position: image.Pt(20, 10)},
+		{ImagePath: filepath.Join(dir, "missing.png"), Position: image.Pt(0, 0)},
+		{ImagePath: filepath.Join(dir, "boxed.png"), Position: image.Pt(100, 10), Size: image.Pt(50, 50)}, // Boxed but missing
+		{ImagePath: panel, Position: image.Pt(180, 90), Size: image.Pt(50, 50)},
+		{ImagePath: panel, Position: image.Pt(100, 60), Style: styled},
+		{ImagePath: dark, Position: image.Pt(0, 60), Night: assets.NightHide},   // Hidden for the night
+		{ImagePath: panel, Position: image.Pt(60, 80), Night: assets.NightHide}, // Bright enough to show
+	}
+
+	c := &Compositor{} // Not daylight
+	got := c.layerRegions(layers, 5, image.Rect(0, 0, 300, 200))
+	want := []image.Rectangle{
+		image.Rect(15, 5, 65, 45),
+		image.Rect(175, 85, 235, 145),
+		image.Rect(77, 39, 175, 127), // Shadow (82,44)-(170,122) covers the plate (90,50)-(150,100), plus padding
+		image.Rect(55, 75, 105, 115),
+	}
+	if len(got) != len(want) {
+		t.Fatalf("layerRegions() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("region %d = %v, want %v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestBackdropBehind(t *testing.T) {
+	dir := t.TempDir()
+	panel := filepath.Join(dir, "panel.png")
+	writeTestPNG(t, panel, testCanvas(60, 40, color.White))
+
+	canvas := checkerCanvas(200, 120)
+	orig := image.NewRGBA(canvas.Rect)
+	copy(orig.Pix, canvas.Pix)
+
+	c := &Compositor{}
+	bd := assets.Backdrop{Blur: 4, Dim: 0.5, Padding: 6}
+	c.applyBackdrop(canvas, bd, []assets.CompositeLayer{{ImagePath: panel, Position: image.Pt(70, 40)}})
+
+	// Outside the padded layer: untouched
+	for _, p := range []image.Point{{10, 10}, {63, 60}, {137, 60}, {100, 33}, {100, 86}} {
+		if canvas.RGBAAt(p.X, p.Y) != orig.RGBAAt(p.X, p.Y) {
+			t.Errorf("pixel %v outside the region changed", p)
+		}
+	}
+
+	// Inside: the checkerboard is smoothed toward its mean, then halved
+	lo, hi := uint8(255), uint8(0)
+	for y := 40; y < 80; y++ {
+		for x := 70; x < 130; x++ {
+			r := canvas.RGBAAt(x, y).R
+			lo, hi = min(lo, r), max(hi, r)
+		}
+	}
+	if lo < 45 || hi > 70 {
+		t.Errorf("red inside the region spans %d-%d, want about 55 (blurred 110, dimmed by half)", lo, hi)
+	}
+}
+
+func TestVignette(t *testing.T) {
+	img := testCanvas(400, 200, color.RGBA{200, 200, 200, 255})
+	vignette(img, 0.5)
+
+	if got := img.RGBAAt(200, 100).R; got != 200 {
+		t.Errorf("center = %d, want unchanged 200", got)
+	}
+	if got := img.RGBAAt(0, 0).R; got < 98 || got > 106 {
+		t.Errorf("corner = %d, want about 100", got)
+	}
+	if mid, corner := img.RGBAAt(0, 100).R, img.RGBAAt(0, 0).R; mid <= corner || mid >= 200 {
+		t.Errorf("edge middle = %d, want between the corner (%d) and the center", mid, corner)
+	}
+}
+
+func TestBoxBlurWorkersMatchesSerial(t *testing.T) {
+	img := checkerCanvas(301, 157)
+	serial := image.NewRGBA(img.Rect)
+	copy(serial.Pix, img.Pix)
+
+	boxBlur(serial.Pix, 301, 157, serial.Stride, 4, 5)
+	boxBlurWorkers(img.Pix, 301, 157, img.Stride, 4, 5, 4)
+
+	if !bytes.Equal(img.Pix, serial.Pix) {
+		t.Error("parallel box blur differs from the serial one")
+	}
+}
+
+func TestBoxLineRoundedMean(t *testing.T) {
+	// Widths up to 1001 with every byte at its maximum, where the multiply-shift mean has the least headroom
+	for radius := 0; radius <= 500; radius += 7 {
+		width := 2*radius + 1
+		n := width + 3
+		pix := make([]uint8, n)
+		for i := range pix {
+			pix[i] = uint8(255 - i%3)
+		}
+		want := make([]uint8, n)
+		for i := range want {
+			sum := 0
+			for k := i - radius; k <= i+radius; k++ {
+				sum += int(pix[min(max(k, 0), n-1)])
+			}
+			want[i] = uint8((sum + width/2) / width)
+		}
+
+		boxLine(pix, make([]uint8, n+2*radius), n, 1, 1, radius)
+		if !bytes.Equal(pix, want) {
+			t.Fatalf("radius %d: boxLine() = %v, want %v", radius, pix[:8], want[:8])
+		}
+	}
+}
+
+func TestBackdropGolden(t *testing.T) {
+	dir := t.TempDir()
+	panel := filepath.Join(dir, "panel.png")
+	writeTestPNG(t, panel, testCanvas(60, 40, color.White))
+
+	canvas := checkerCanvas(200, 120)
+	c := &Compositor{}
+	c.applyBackdrop(canvas, assets.Backdrop{Blur: 3, Dim: 0.3, Padding: 8, Vignette: 0.4},
+		[]assets.CompositeLayer{{ImagePath: panel, Position: image.Pt(20, 20)}, {ImagePath: panel, Position: image.Pt(60, 50)}})
+	checkGolden(t, "backdrop_behind", canvas)
+}
+
+// BenchmarkBackdropGlobal blurs and dims a full 4K canvas
+func BenchmarkBackdropGlobal(b *testing.B) {
+	canvas := checkerCanvas(3840, 2160)
+	c := &Compositor{}
+	bd := assets.Backdrop{Scope: assets.BackdropGlobal, Blur: 6, Dim: 0.3, Vignette: 0.25}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		c.applyBackdrop(canvas, bd, nil)
+	}
+}
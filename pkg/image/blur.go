@@ -0,0 +1,202 @@
+package image
+
+import (
+	"image"
+	"math"
+	"sync"
+)
+
+// gaussianKernel returns normalized 16.16 fixed-point weights for a Gaussian of sigma
+// The kernel covers 3 sigma on each side
+func gaussianKernel(sigma float64) []int32 {
+	radius := int(math.Ceil(sigma * 3))
+	weights := make([]float64, 2*radius+1)
+	var sum float64
+	for i := range weights {
+		d := float64(i - radius)
+		weights[i] = math.Exp(-d * d / (2 * sigma * sigma))
+		sum += weights[i]
+	}
+
+	kernel := make([]int32, len(weights))
+	for i, w := range weights {
+		kernel[i] = int32(math.Round(w / sum * (1 << 16)))
+	}
+	return kernel
+}
+
+// gaussianBlur returns a blurred copy of src (separable, edges extended)
+// All four channels are blurred; src must have its origin at (0, 0)
+func gaussianBlur(src *image.NRGBA, sigma float64) *image.NRGBA {
+	if sigma <= 0 {
+		dst := image.NewNRGBA(src.Rect)
+		copy(dst.Pix, src.Pix)
+		return dst
+	}
+
+	kernel := gaussianKernel(sigma)
+	tmp := image.NewNRGBA(src.Rect)
+	dst := image.NewNRGBA(src.Rect)
+	convolve(tmp, src, kernel, false)
+	convolve(dst, tmp, kernel, true)
+	return dst
+}
+
+// convolve applies kernel along rows (or columns if vertical), extending the edges
+func convolve(dst, src *image.NRGBA, kernel []int32, vertical bool) {
+	radius := len(kernel) / 2
+	w, h := src.Rect.Dx(), src.Rect.Dy()
+
+	// Byte distance between neighbours on the axis, and the axis length
+	step, length := 4, w
+	if vertical {
+		step, length = src.Stride, h
+	}
+
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			pos := x
+			if vertical {
+				pos = y
+			}
+			i := y*src.Stride + x*4
+
+			var r, g, b, a int32
+			for k, weight := range kernel {
+				n := min(max(pos+k-radius, 0), length-1) - pos
+				j := i + n*step
+				r += weight * int32(src.Pix[j])
+				g += weight * int32(src.Pix[j+1])
+				b += weight * int32(src.Pix[j+2])
+				a += weight * int32(src.Pix[j+3])
+			}
+
+			dst.Pix[i] = clampByte((r + 1<<15) >> 16)
+			dst.Pix[i+1] = clampByte((g + 1<<15) >> 16)
+			dst.Pix[i+2] = clampByte((b + 1<<15) >> 16)
+			dst.Pix[i+3] = clampByte((a + 1<<15) >> 16)
+		}
+	}
+}
+
+// clampByte clamps v to 0-255
+func clampByte(v int32) uint8 {
+	if v < 0 {
+		return 0
+	}
+	if v > 255 {
+		return 255
+	}
+	return uint8(v)
+}
+
+// boxSizes returns the widths of three box blurs approximating a Gaussian of sigma
+// (http://blog.ivank.net/fastest-gaussian-blur.html)
+func boxSizes(sigma float64) [3]int {
+	const n = 3
+	ideal := math.Sqrt(12*sigma*sigma/n + 1)
+	lower := int(ideal)
+	if lower%2 == 0 {
+		lower--
+	}
+	upper := lower + 2
+	m := int(math.Round((12*sigma*sigma - n*float64(lower*lower) - 4*n*float64(lower) - 3*n) / (-4*float64(lower) - 4)))
+
+	var sizes [3]int
+	for i := range sizes {
+		sizes[i] = upper
+		if i < m {
+			sizes[i] = lower
+		}
+	}
+	return sizes
+}
+
+// boxBlur approximates a Gaussian blur of sigma in place with three box blurs per axis
+// pix holds w x h pixels of channels bytes each, rows stride bytes apart
+// Cost per pixel is independent of sigma
+func boxBlur(pix []uint8, w, h, stride, channels int, sigma float64) {
+	boxBlurWorkers(pix, w, h, stride, channels, sigma, 1)
+}
+
+// boxBlurWorkers is boxBlur with the rows (then columns) of each pass split over workers
+func boxBlurWorkers(pix []uint8, w, h, stride, channels int, sigma float64, workers int) {
+	if sigma <= 0 || w == 0 || h == 0 {
+		return
+	}
+
+	sizes := boxSizes(sigma)
+	scratch := (max(w, h) + 2*(sizes[2]/2)) * channels // Sizes are ascending
+	for _, size := range sizes {
+		radius := size / 2
+		if radius == 0 {
+			continue
+		}
+		eachLine(h, workers, scratch, func(y int, line []uint8) {
+			boxLine(pix[y*stride:], line, w, channels, channels, radius)
+		})
+		eachLine(w, workers, scratch, func(x int, line []uint8) {
+			boxLine(pix[x*channels:], line, h, stride, channels, radius)
+		})
+	}
+}
+
+// eachLine calls fn for lines 0..n-1, split into contiguous runs over workers
+// Each worker gets its own scratch buffer of size bytes
+func eachLine(n, workers, size int, fn func(i int, line []uint8)) {
+	workers = min(max(workers, 1), n)
+	if workers == 1 {
+		line := make([]uint8, size)
+		for i := 0; i < n; i++ {
+			fn(i, line)
+		}
+		return
+	}
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(from, to int) {
+			defer wg.Done()
+			line := make([]uint8, size)
+			for i := from; i < to; i++ {
+				fn(i, line)
+			}
+		}(n*w/workers, n*(w+1)/workers)
+	}
+	wg.Wait()
+}
+
+// boxLine box-blurs n pixels step bytes apart starting at pix[0], extending the edges
+// line is scratch space for (n+2*radius)*channels bytes; channels is at most 4
+func boxLine(pix, line []uint8, n, step, channels, radius int) {
+	// Copy the pixels with radius edge pixels on each side, so the window needs no clamping
+	ext := line[:(n+2*radius)*channels]
+	for i := 0; i < n+2*radius; i++ {
+		src := min(max(i-radius, 0), n-1) * step
+		for c := 0; c < channels; c++ {
+			ext[i*channels+c] = pix[src+c]
+		}
+	}
+
+	// Rounded mean by multiply and shift (exact for these sums) instead of a division per byte
+	// 64-bit on every platform: the product needs ~40 bits
+	width := 2*radius + 1
+	w := uint64(width)
+	inv := (uint64(1)<<32 + w - 1) / w
+	var sum [4]uint64
+	for i := 0; i < width*channels; i += channels {
+		for c := 0; c < channels; c++ {
+			sum[c] += uint64(ext[i+c])
+		}
+	}
+	for i := 0; i < n; i++ {
+		out, in, drop := i*step, (i+width)*channels, i*channels
+		for c := 0; c < channels; c++ {
+			pix[out+c] = uint8((sum[c] + w/2) * inv >> 32)
+			if i+1 < n {
+				sum[c] = sum[c] + uint64(ext[in+c]) - uint64(ext[drop+c]) // Never negative, so no wraparound
+			}
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package image
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"os"
+	"time"
+
+	"github.com/trodemaster/weatherdesktop/pkg/assets"
+	"github.com/trodemaster/weatherdesktop/pkg/history"
+	"github.com/trodemaster/weatherdesktop/pkg/parser"
+	"golang.org/x/image/font"
+	"golang.org/x/image/math/fixed"
+)
+
+// Label layout
+const (
+	labelMargin     = 12 // Distance from the layer's edge
+	labelPadding    = 8  // Plate padding around backed text
+	labelOutline    = 2  // Outline width of outlined text
+	captionStaleAge = 30 * time.Minute
+)
+
+var (
+	labelPlate        = color.RGBA{0, 0, 0, 170}
+	labelOutlineColor = color.RGBA{0, 0, 0, 255}
+)
+
+// drawCaption labels the visible rect of a layer with its caption
+func (c *Compositor) drawCaption(canvas *image.RGBA, visible image.Rectangle, caption assets.Caption) {
+	text := captionText(caption, c.clock())
+	drawLabel(canvas, visible, text, c.labelFace(), caption.Placement, caption.Style)
+}
+
+// clock returns the render time
+func (c *Compositor) clock() time.Time {
+	if c.now == nil {
+		return time.Now()
+	}
+	return c.now()
+}
+
+// captionText returns the caption's text with the capture time of its TimeFrom file
+// Captures older than captionStaleAge also show their age; older than a day, their date
+func captionText(caption assets.Caption, now time.Time) string {
+	if caption.TimeFrom == "" {
+		return caption.Text
+	}
+	info, err := os.Stat(caption.TimeFrom)
+	if err != nil {
+		return caption.Text
+	}
+	return caption.Text + " - " + captureTime(info.ModTime(), now)
+}
+
+// captureTime formats a capture time in Pacific time relative to now
+func captureTime(t, now time.Time) string {
+	local := t.In(parser.Pacific)
+	age := now.Sub(t)
+
+	layout := "3:04 PM"
+	if age >= 24*time.Hour {
+		layout = "Jan 2 3:04 PM"
+	}
+	text := local.Format(layout)
+	if age >= captionStaleAge {
+		text += " (" + history.FormatDuration(age) + " ago)"
+	}
+	return text
+}
+
+// drawLabel draws text at placement relative to box, backed by a plate or outlined
+func drawLabel(dst draw.Image, box image.Rectangle, text string, face font.Face, placement assets.CaptionPlacement, style assets.CaptionStyle) {
+	d := &font.Drawer{Dst: dst, Face: face}
+	metrics := face.Metrics()
+
+	// Plate-sized rect for both styles so outlined labels line up with backed ones
+	plate := labelRect(box, image.Point{
+		X: d.MeasureString(text).Ceil() + 2*labelPadding,
+		Y: (metrics.Ascent + metrics.Descent).Ceil() + 2*labelPadding,
+	}, placement)
+	dot := fixed.Point26_6{
+		X: fixed.I(plate.Min.X + labelPadding),
+		Y: fixed.I(plate.Min.Y+labelPadding) + metrics.Ascent,
+	}
+
+	if style == assets.CaptionOutlined {
+		d.Src = image.NewUniform(labelOutlineColor)
+		for dy := -labelOutline; dy <= labelOutline; dy++ {
+			for dx := -labelOutline; dx <= labelOutline; dx++ {
+				if dx == 0 && dy == 0 {
+					continue
+				}
+				d.Dot = dot.Add(fixed.P(dx, dy))
+				d.DrawString(text)
+			}
+		}
+	} else {
+		draw.Draw(dst, plate, image.NewUniform(labelPlate), image.Point{}, draw.Over)
+	}
+
+	d.Src = image.NewUniform(color.White)
+	d.Dot = dot
+	d.DrawString(text)
+}
+
+// labelRect places a label of size relative to box
+func labelRect(box image.Rectangle, size image.Point, placement assets.CaptionPlacement) image.Rectangle {
+	var at image.Point
+	switch placement {
+	case assets.CaptionTopRight:
+		at = image.Pt(box.Max.X-labelMargin-size.X, box.Min.Y+labelMargin)
+	case assets.CaptionBottomLeft:
+		at = image.Pt(box.Min.X+labelMargin, box.Max.Y-labelMargin-size.Y)
+	case assets.CaptionBottomRight:
+		at = image.Pt(box.Max.X-labelMargin-size.X, box.Max.Y-labelMargin-size.Y)
+	case assets.CaptionBelow:
+		at = image.Pt(box.Min.X, box.Max.Y+labelMargin/2)
+	default:
+		at = image.Pt(box.Min.X+labelMargin, box.Min.Y+labelMargin)
+	}
+	return image.Rectangle{Min: at, Max: at.Add(size)}
+}
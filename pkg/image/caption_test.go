@@ -0,0 +1,104 @@
+package image
+
+import (
+	"image"
+	"image/color"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/trodemaster/weatherdesktop/pkg/assets"
+	"github.com/trodemaster/weatherdesktop/pkg/parser"
+	"golang.org/x/image/font/basicfont"
+)
+
+func TestCaptureTime(t *testing.T) {
+	now := time.Date(2025, 1, 15, 16, 0, 0, 0, parser.Pacific)
+
+	tests := []struct {
+		name string
+		at   time.Time
+		want string
+	}{
+		{"fresh", now.Add(-5 * time.Minute), "3:55 PM"},
+		{"stale", now.Add(-95 * time.Minute), "2:25 PM (1h 35m ago)"},
+		{"old", now.Add(-50 * time.Hour), "Jan 13 2:00 PM (2d 2h ago)"},
+		{"utc input", now.Add(-10 * time.Minute).UTC(), "3:50 PM"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := captureTime(tt.at, now); got != tt.want {
+				t.Errorf("captureTime() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCaptionText(t *testing.T) {
+	now := time.Date(2025, 1, 15, 16, 0, 0, 0, parser.Pacific)
+	path := filepath.Join(t.TempDir(), "cam.jpg")
+	if err := os.WriteFile(path, []byte("jpeg"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	modified := now.Add(-2 * time.Minute)
+	if err := os.Chtimes(path, modified, modified); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := captionText(assets.Caption{Text: "Cam", TimeFrom: path}, now); got != "Cam - 3:58 PM" {
+		t.Errorf("captionText() = %q", got)
+	}
+	if got := captionText(assets.Caption{Text: "Cam", TimeFrom: path + ".missing"}, now); got != "Cam" {
+		t.Errorf("captionText(missing file) = %q, want the name only", got)
+	}
+	if got := captionText(assets.Caption{Text: "Cam"}, now); got != "Cam" {
+		t.Errorf("captionText(no time) = %q, want the name only", got)
+	}
+}
+
+func TestLabelRect(t *testing.T) {
+	box := image.Rect(100, 100, 400, 300)
+	size := image.Pt(80, 30)
+
+	tests := []struct {
+		placement assets.CaptionPlacement
+		want      image.Point
+	}{
+		{assets.CaptionTopLeft, image.Pt(112, 112)},
+		{assets.CaptionTopRight, image.Pt(308, 112)},
+		{assets.CaptionBottomLeft, image.Pt(112, 258)},
+		{assets.CaptionBottomRight, image.Pt(308, 258)},
+		{assets.CaptionBelow, image.Pt(100, 306)},
+	}
+
+	for _, tt := range tests {
+		got := labelRect(box, size, tt.placement)
+		if got.Min != tt.want || got.Size() != size {
+			t.Errorf("labelRect(%q) = %v, want %v at %v", tt.placement, got, size, tt.want)
+		}
+	}
+}
+
+func TestCaptionGolden(t *testing.T) {
+	tests := []struct {
+		name      string
+		placement assets.CaptionPlacement
+		style     assets.CaptionStyle
+	}{
+		{"backed", assets.CaptionTopLeft, assets.CaptionBacked},
+		{"outlined", assets.CaptionBottomRight, assets.CaptionOutlined},
+		{"below", assets.CaptionBelow, assets.CaptionBacked},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			canvas := checkerCanvas(200, 110)
+			box := image.Rect(10, 10, 190, 80)
+			fill(canvas, box, color.RGBA{230, 230, 220, 255}) // Bright layer: worst case for white text
+			drawLabel(canvas, box, "Cam - 3:58 PM", basicfont.Face7x13, tt.placement, tt.style)
+			checkGolden(t, "caption_"+tt.name, canvas)
+		})
+	}
+}
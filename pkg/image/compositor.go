@@ -5,25 +5,37 @@ import (
 	"image"
 	"image/color"
 	"image/draw"
-	"image/jpeg"
 	"log"
 	"os"
+	"time"
 
 	"github.com/trodemaster/weatherdesktop/pkg/assets"
+	"github.com/trodemaster/weatherdesktop/pkg/parser"
+	"github.com/trodemaster/weatherdesktop/pkg/sun"
 )
 
 // Compositor handles compositing multiple images into a single output
 type Compositor struct {
-	manager *assets.Manager
+	manager  *assets.Manager
+	now      func() time.Time
+	daylight bool                // Sun above civil twilight at render time
+	text     *TextRenderer       // Layer labels; loaded on first use
+	passes   []parser.PassResult // Pass status for the HUD strip
 }
 
 // NewCompositor creates a new compositor
 func NewCompositor(manager *assets.Manager) *Compositor {
 	return &Compositor{
 		manager: manager,
+		now:     time.Now,
 	}
 }
 
+// SetPassResults sets the parsed pass status shown in the HUD strip
+func (c *Compositor) SetPassResults(passes []parser.PassResult) {
+	c.passes = passes
+}
+
 // Render creates the final composite image
 func (c *Compositor) Render(outputPath string) error {
 	// Create canvas: 3840x2160 with sky blue background
@@ -33,19 +45,37 @@ func (c *Compositor) Render(outputPath string) error {
 	skyBlue := color.RGBA{135, 206, 235, 255} // RGB(135, 206, 235)
 	draw.Draw(canvas, canvas.Bounds(), &image.Uniform{skyBlue}, image.Point{}, draw.Src)
 	
+	// Webcam layers switch to their night mode after civil dusk
+	now := c.now()
+	loc := c.manager.GetLocation()
+	c.daylight = sun.IsDaylight(now, loc.Latitude, loc.Longitude)
+	if dawn, dusk, ok := sun.CivilTwilight(now.In(parser.Pacific), loc.Latitude, loc.Longitude); ok {
+		log.Printf("%s civil twilight %s - %s (daylight: %v)", loc.Name, dawn.Format("3:04 PM"), dusk.Format("3:04 PM"), c.daylight)
+	}
+	
 	// Get composite layout
 	layers := c.manager.GetCompositeLayout()
 	
 	// Composite each layer
-	for _, layer := range layers {
+	for i, layer := range layers {
 		if err := c.compositeLayer(canvas, layer); err != nil {
 			log.Printf("Warning: Failed to composite %s: %v", layer.ImagePath, err)
 			// Continue with other layers even if one fails
 		}
+		
+		// Soften the background behind the layers still to come
+		if layer.Backdrop != nil {
+			c.applyBackdrop(canvas, *layer.Backdrop, layers[i+1:])
+		}
+	}
+	
+	// Status strip over everything else
+	if hud := c.manager.GetHUD(); hud != nil {
+		c.drawHUD(canvas, *hud)
 	}
 	
 	// Save the final composite
-	if err := c.saveJPEG(canvas, outputPath); err != nil {
+	if err := saveEncoded(canvas, outputPath, c.manager.GetRenderEncoding()); err != nil {
 		return fmt.Errorf("failed to save composite: %w", err)
 	}
 	
@@ -66,33 +96,42 @@ func (c *Compositor) compositeLayer(canvas *image.RGBA, layer assets.CompositeLa
 		return fmt.Errorf("failed to load layer image: %w", err)
 	}
 	
+	// Night policy for webcam layers (may hide the layer)
+	if layer.Night != assets.NightShow {
+		if layerImg = c.nightFrame(layer, layerImg); layerImg == nil {
+			log.Printf("Skipped %s (dark)", layer.ImagePath)
+			return nil
+		}
+	}
+	
 	// Calculate destination rectangle
 	bounds := layerImg.Bounds()
+	position := layer.Position
+	dst := canvas
+	if layer.Size != (image.Point{}) {
+		// Align inside the layer box (e.g. a letterboxed contain result) and clip to it
+		position = position.Add(alignOffset(layer.Size, bounds.Size(), layer.Gravity))
+		dst = canvas.SubImage(image.Rectangle{Min: layer.Position, Max: layer.Position.Add(layer.Size)}).(*image.RGBA)
+	}
 	destRect := image.Rectangle{
-		Min: layer.Position,
-		Max: layer.Position.Add(image.Point{X: bounds.Dx(), Y: bounds.Dy()}),
+		Min: position,
+		Max: position.Add(image.Point{X: bounds.Dx(), Y: bounds.Dy()}),
 	}
 	
 	// Composite the image onto the canvas using Over operation (alpha blending)
-	draw.Draw(canvas, destRect, layerImg, bounds.Min, draw.Over)
-	
-	log.Printf("Composited %s at position (%d, %d)", layer.ImagePath, layer.Position.X, layer.Position.Y)
-	return nil
-}
-
-// saveJPEG saves an image as JPEG with high quality
-func (c *Compositor) saveJPEG(img image.Image, path string) error {
-	f, err := os.Create(path)
-	if err != nil {
-		return fmt.Errorf("failed to create file: %w", err)
+	visible := destRect.Intersect(dst.Bounds())
+	if layer.Style != nil {
+		// Effects follow the visible part of the layer; shadow and plate may extend past its box
+		drawStyled(canvas, visible, layerImg, bounds.Min.Add(visible.Min.Sub(destRect.Min)), *layer.Style)
+	} else {
+		draw.Draw(dst, destRect, layerImg, bounds.Min, draw.Over)
 	}
-	defer f.Close()
 	
-	opts := &jpeg.Options{Quality: 90}
-	if err := jpeg.Encode(f, img, opts); err != nil {
-		return fmt.Errorf("failed to encode JPEG: %w", err)
+	// Caption on top of the layer (or just below it)
+	if layer.Caption != nil {
+		c.drawCaption(canvas, visible, *layer.Caption)
 	}
 	
+	log.Printf("Composited %s at position (%d, %d)", layer.ImagePath, position.X, position.Y)
 	return nil
 }
-
@@ -0,0 +1,142 @@
+package image
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+
+	"github.com/trodemaster/weatherdesktop/pkg/assets"
+)
+
+// Effect defaults
+var (
+	defaultBorderColor = color.RGBA{255, 255, 255, 255}
+	defaultShadowColor = color.NRGBA{0, 0, 0, 140}
+	defaultPlateColor  = color.NRGBA{0, 0, 0, 115}
+)
+
+// drawStyled composites img onto dst at rect with the layer's style:
+// plate, then shadow, then the image with rounded corners, then the border
+// sp is the point in img drawn at rect.Min
+func drawStyled(dst *image.RGBA, rect image.Rectangle, img image.Image, sp image.Point, style assets.LayerStyle) {
+	size := rect.Size()
+	if size.X <= 0 || size.Y <= 0 {
+		return
+	}
+	radius := float64(style.CornerRadius)
+
+	opacity := style.Opacity
+	if opacity <= 0 || opacity > 1 {
+		opacity = 1
+	}
+
+	src := image.NewRGBA(image.Rect(0, 0, size.X, size.Y))
+	draw.Draw(src, src.Bounds(), img, sp, draw.Src)
+	corners := roundedRectMask(size, 0, radius)
+
+	if plate := style.Plate; plate != nil {
+		plateRect := rect.Inset(-plate.Padding)
+		plateRadius := 0.0
+		if radius > 0 {
+			plateRadius = radius + float64(plate.Padding)
+		}
+		mask := roundedRectMask(plateRect.Size(), 0, plateRadius)
+		draw.DrawMask(dst, plateRect, image.NewUniform(colorOr(plate.Color, defaultPlateColor)), image.Point{}, mask, image.Point{}, draw.Over)
+	}
+
+	if shadow := style.Shadow; shadow != nil {
+		drawShadow(dst, rect, src, corners, *shadow, opacity)
+	}
+
+	// Image clipped to the rounded corners
+	scaleAlpha(corners, opacity)
+	draw.DrawMask(dst, rect, src, image.Point{}, corners, image.Point{}, draw.Over)
+
+	if style.BorderWidth > 0 {
+		// Ring between the outer edge and the edge inset by the border width
+		ring := roundedRectMask(size, 0, radius)
+		inner := roundedRectMask(size, float64(style.BorderWidth), math.Max(radius-float64(style.BorderWidth), 0))
+		for i := range ring.Pix {
+			ring.Pix[i] -= min(inner.Pix[i], ring.Pix[i])
+		}
+		scaleAlpha(ring, opacity)
+		draw.DrawMask(dst, rect, image.NewUniform(colorOr(style.BorderColor, defaultBorderColor)), image.Point{}, ring, image.Point{}, draw.Over)
+	}
+}
+
+// styledBounds returns the area drawStyled may cover for a layer at rect: the layer, its plate and its shadow
+func styledBounds(rect image.Rectangle, style assets.LayerStyle) image.Rectangle {
+	bounds := rect
+	if plate := style.Plate; plate != nil {
+		bounds = bounds.Union(rect.Inset(-plate.Padding))
+	}
+	if shadow := style.Shadow; shadow != nil {
+		pad := int(math.Ceil(shadow.Blur * 3))
+		bounds = bounds.Union(rect.Add(shadow.Offset).Inset(-pad))
+	}
+	return bounds
+}
+
+// drawShadow draws a blurred copy of the layer's shape (corners and image alpha) under it
+func drawShadow(dst *image.RGBA, rect image.Rectangle, src *image.RGBA, corners *image.Alpha, shadow assets.Shadow, opacity float64) {
+	pad := int(math.Ceil(shadow.Blur * 3))
+	size := rect.Size()
+
+	mask := image.NewAlpha(image.Rect(0, 0, size.X+2*pad, size.Y+2*pad))
+	for y := 0; y < size.Y; y++ {
+		for x := 0; x < size.X; x++ {
+			a := uint32(corners.Pix[y*corners.Stride+x]) * uint32(src.Pix[y*src.Stride+x*4+3]) / 255
+			mask.Pix[(y+pad)*mask.Stride+x+pad] = uint8(a)
+		}
+	}
+	boxBlur(mask.Pix, mask.Rect.Dx(), mask.Rect.Dy(), mask.Stride, 1, shadow.Blur)
+	scaleAlpha(mask, opacity)
+
+	at := rect.Min.Add(shadow.Offset).Sub(image.Point{X: pad, Y: pad})
+	draw.DrawMask(dst, mask.Rect.Add(at), image.NewUniform(colorOr(shadow.Color, defaultShadowColor)), image.Point{}, mask, image.Point{}, draw.Over)
+}
+
+// roundedRectMask returns the anti-aliased coverage of a rounded rectangle filling size,
+// inset by inset pixels on every side, with corner radius
+func roundedRectMask(size image.Point, inset, radius float64) *image.Alpha {
+	mask := image.NewAlpha(image.Rect(0, 0, size.X, size.Y))
+
+	halfW := float64(size.X)/2 - inset
+	halfH := float64(size.Y)/2 - inset
+	radius = math.Min(radius, math.Min(halfW, halfH))
+	if halfW <= 0 || halfH <= 0 {
+		return mask
+	}
+
+	for y := 0; y < size.Y; y++ {
+		for x := 0; x < size.X; x++ {
+			// Signed distance from the pixel center to the rounded rectangle's edge
+			qx := math.Abs(float64(x)+0.5-float64(size.X)/2) - (halfW - radius)
+			qy := math.Abs(float64(y)+0.5-float64(size.Y)/2) - (halfH - radius)
+			d := math.Hypot(math.Max(qx, 0), math.Max(qy, 0)) + math.Min(math.Max(qx, qy), 0) - radius
+
+			coverage := math.Min(math.Max(0.5-d, 0), 1)
+			mask.Pix[y*mask.Stride+x] = uint8(math.Round(coverage * 255))
+		}
+	}
+	return mask
+}
+
+// scaleAlpha multiplies every mask value by factor (no-op at 1)
+func scaleAlpha(mask *image.Alpha, factor float64) {
+	if factor >= 1 {
+		return
+	}
+	for i, a := range mask.Pix {
+		mask.Pix[i] = uint8(math.Round(float64(a) * factor))
+	}
+}
+
+// colorOr returns c, or def if c is nil
+func colorOr(c, def color.Color) color.Color {
+	if c == nil {
+		return def
+	}
+	return c
+}
@@ -0,0 +1,122 @@
+package image
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"path/filepath"
+	"testing"
+
+	"github.com/trodemaster/weatherdesktop/pkg/assets"
+)
+
+// checkerCanvas returns a two-tone checkerboard so translucent effects are visible
+func checkerCanvas(w, h int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			c := color.RGBA{70, 110, 150, 255}
+			if (x/10+y/10)%2 == 0 {
+				c = color.RGBA{150, 180, 210, 255}
+			}
+			img.SetRGBA(x, y, c)
+		}
+	}
+	return img
+}
+
+func TestLayerStyleGolden(t *testing.T) {
+	// 100x60 "camera" layer: warm gradient with a dark band
+	layerImg := image.NewRGBA(image.Rect(0, 0, 100, 60))
+	for y := 0; y < 60; y++ {
+		for x := 0; x < 100; x++ {
+			c := color.RGBA{uint8(200 + x/2), uint8(120 + y), 60, 255}
+			if y >= 40 && y < 48 {
+				c = color.RGBA{40, 40, 40, 255}
+			}
+			layerImg.SetRGBA(x, y, c)
+		}
+	}
+	path := filepath.Join(t.TempDir(), "layer.png")
+	writeTestPNG(t, path, layerImg)
+
+	tests := []struct {
+		name  string
+		style assets.LayerStyle
+	}{
+		{"border", assets.LayerStyle{BorderWidth: 3, BorderColor: color.RGBA{255, 255, 255, 255}}},
+		{"rounded", assets.LayerStyle{CornerRadius: 14}},
+		{"shadow", assets.LayerStyle{Shadow: &assets.Shadow{Offset: image.Pt(6, 8), Blur: 5}}},
+		{"opacity", assets.LayerStyle{Opacity: 0.5}},
+		{"plate", assets.LayerStyle{CornerRadius: 8, Plate: &assets.Plate{Padding: 10}}},
+		{"panel", assets.LayerStyle{
+			CornerRadius: 10,
+			BorderWidth:  2,
+			BorderColor:  color.NRGBA{255, 255, 255, 170},
+			Shadow:       &assets.Shadow{Offset: image.Pt(6, 8), Blur: 8},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			canvas := checkerCanvas(160, 120)
+			style := tt.style
+			c := &Compositor{}
+			err := c.compositeLayer(canvas, assets.CompositeLayer{ImagePath: path, Position: image.Pt(25, 20), Style: &style})
+			if err != nil {
+				t.Fatalf("compositeLayer() error: %v", err)
+			}
+			checkGolden(t, "style_"+tt.name, canvas)
+		})
+	}
+}
+
+func TestRoundedRectMask(t *testing.T) {
+	mask := roundedRectMask(image.Pt(40, 30), 0, 10)
+	if a := mask.AlphaAt(0, 0).A; a != 0 {
+		t.Errorf("corner coverage = %d, want 0", a)
+	}
+	if a := mask.AlphaAt(20, 15).A; a != 255 {
+		t.Errorf("center coverage = %d, want 255", a)
+	}
+	if a := mask.AlphaAt(20, 0).A; a != 255 {
+		t.Errorf("straight edge coverage = %d, want 255", a)
+	}
+	// Anti-aliased: the top row is partially covered where the arc meets it
+	partial := false
+	for i := 0; i < 10; i++ {
+		if a := mask.AlphaAt(i, 0).A; a > 0 && a < 255 {
+			partial = true
+		}
+	}
+	if !partial {
+		t.Error("no partially covered pixels along the corner arc")
+	}
+}
+
+func TestBoxBlurApproximatesGaussian(t *testing.T) {
+	// Single bright column: the blurred profile should follow a Gaussian of sigma
+	const w, h, sigma = 81, 3, 6.0
+	img := image.NewAlpha(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 40 - 4; x <= 40+4; x++ {
+			img.Pix[y*img.Stride+x] = 255
+		}
+	}
+	boxBlur(img.Pix, w, h, img.Stride, 1, sigma)
+
+	ref := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 40 - 4; x <= 40+4; x++ {
+			ref.Pix[y*ref.Stride+x*4+3] = 255
+		}
+	}
+	ref = gaussianBlur(ref, sigma)
+
+	for x := 0; x < w; x++ {
+		got, want := float64(img.Pix[img.Stride+x]), float64(ref.Pix[ref.Stride+x*4+3])
+		if math.Abs(got-want) > 12 {
+			t.Errorf("x=%d: box blur %v, gaussian %v", x, got, want)
+		}
+	}
+}
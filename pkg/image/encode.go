@@ -0,0 +1,200 @@
+package image
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/trodemaster/weatherdesktop/pkg/assets"
+	"golang.org/x/image/draw"
+)
+
+// Default encoder settings
+const (
+	defaultJPEGQuality = 90
+	maxPaletteColors   = 256
+)
+
+// outputFormat resolves the encoder for a path: the explicit format, else the extension
+func outputFormat(path string, enc assets.Encoding) assets.ImageFormat {
+	if enc.Format != assets.FormatAuto {
+		return enc.Format
+	}
+	if strings.EqualFold(filepath.Ext(path), ".png") {
+		return assets.FormatPNG
+	}
+	return assets.FormatJPEG
+}
+
+// saveEncoded writes an image to path with the given encoding
+func saveEncoded(img image.Image, path string, enc assets.Encoding) error {
+	format := outputFormat(path, enc)
+	if format != assets.FormatJPEG && format != assets.FormatPNG {
+		return fmt.Errorf("unknown image format %q", format)
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+	defer f.Close()
+
+	if format == assets.FormatPNG {
+		if enc.Colors > 0 {
+			img = quantize(img, enc.Colors, enc.Dither)
+		}
+		encoder := png.Encoder{CompressionLevel: png.BestCompression}
+		if err := encoder.Encode(f, img); err != nil {
+			return fmt.Errorf("failed to encode PNG: %w", err)
+		}
+		return nil
+	}
+
+	quality := enc.Quality
+	if quality <= 0 {
+		quality = defaultJPEGQuality
+	}
+	if err := jpeg.Encode(f, img, &jpeg.Options{Quality: min(quality, 100)}); err != nil {
+		return fmt.Errorf("failed to encode JPEG: %w", err)
+	}
+	return nil
+}
+
+// colorBucket accumulates the pixels of one histogram bucket (5 bits per channel)
+type colorBucket struct {
+	r, g, b, a uint64
+	n          uint64
+}
+
+// mean returns the bucket's average premultiplied color
+func (c colorBucket) mean() color.RGBA {
+	return color.RGBA{
+		R: uint8(c.r / c.n),
+		G: uint8(c.g / c.n),
+		B: uint8(c.b / c.n),
+		A: uint8(c.a / c.n),
+	}
+}
+
+// channel returns the bucket's average for channel i (0-3 = R, G, B, A)
+func (c colorBucket) channel(i int) uint64 {
+	switch i {
+	case 0:
+		return c.r / c.n
+	case 1:
+		return c.g / c.n
+	case 2:
+		return c.b / c.n
+	}
+	return c.a / c.n
+}
+
+// quantize reduces img to a palette of at most n colors using median cut
+func quantize(img image.Image, n int, dither bool) *image.Paletted {
+	n = max(2, min(n, maxPaletteColors))
+	bounds := img.Bounds()
+
+	// Histogram of 5-bit buckets, keeping the exact averages
+	hist := make(map[uint32]*colorBucket)
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			c := color.RGBAModel.Convert(img.At(x, y)).(color.RGBA)
+			key := uint32(c.R>>3)<<15 | uint32(c.G>>3)<<10 | uint32(c.B>>3)<<5 | uint32(c.A>>3)
+			bucket := hist[key]
+			if bucket == nil {
+				bucket = &colorBucket{}
+				hist[key] = bucket
+			}
+			bucket.r += uint64(c.R)
+			bucket.g += uint64(c.G)
+			bucket.b += uint64(c.B)
+			bucket.a += uint64(c.A)
+			bucket.n++
+		}
+	}
+
+	buckets := make([]colorBucket, 0, len(hist))
+	for _, bucket := range hist {
+		buckets = append(buckets, *bucket)
+	}
+
+	palette := make(color.Palette, 0, n)
+	for _, box := range medianCut(buckets, n) {
+		var sum colorBucket
+		for _, b := range box {
+			sum.r += b.r
+			sum.g += b.g
+			sum.b += b.b
+			sum.a += b.a
+			sum.n += b.n
+		}
+		palette = append(palette, sum.mean())
+	}
+
+	dst := image.NewPaletted(image.Rect(0, 0, bounds.Dx(), bounds.Dy()), palette)
+	if dither {
+		draw.FloydSteinberg.Draw(dst, dst.Bounds(), img, bounds.Min)
+	} else {
+		draw.Draw(dst, dst.Bounds(), img, bounds.Min, draw.Src)
+	}
+	return dst
+}
+
+// medianCut splits buckets into at most n boxes, repeatedly halving (by pixel count)
+// the box with the widest channel range along that channel
+func medianCut(buckets []colorBucket, n int) [][]colorBucket {
+	boxes := [][]colorBucket{buckets}
+
+	for len(boxes) < n {
+		// Pick the box and channel with the widest range
+		best, bestChannel, bestRange := -1, 0, uint64(0)
+		for i, box := range boxes {
+			if len(box) < 2 {
+				continue
+			}
+			for ch := 0; ch < 4; ch++ {
+				lo, hi := box[0].channel(ch), box[0].channel(ch)
+				for _, b := range box[1:] {
+					v := b.channel(ch)
+					lo, hi = min(lo, v), max(hi, v)
+				}
+				if hi-lo > bestRange {
+					best, bestChannel, bestRange = i, ch, hi-lo
+				}
+			}
+		}
+		if best < 0 {
+			break // Every box is a single color
+		}
+
+		box := boxes[best]
+		sort.Slice(box, func(i, j int) bool {
+			return box[i].channel(bestChannel) < box[j].channel(bestChannel)
+		})
+
+		// Split at the weighted median, leaving at least one bucket on each side
+		var total, acc uint64
+		for _, b := range box {
+			total += b.n
+		}
+		split := 1
+		for i, b := range box[:len(box)-1] {
+			acc += b.n
+			if acc >= total/2 {
+				split = i + 1
+				break
+			}
+		}
+
+		boxes[best] = box[:split]
+		boxes = append(boxes, box[split:])
+	}
+
+	return boxes
+}
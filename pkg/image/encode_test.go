@@ -0,0 +1,109 @@
+package image
+
+import (
+	"image"
+	"image/color"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/trodemaster/weatherdesktop/pkg/assets"
+)
+
+func TestOutputFormat(t *testing.T) {
+	tests := []struct {
+		path string
+		enc  assets.Encoding
+		want assets.ImageFormat
+	}{
+		{"a/forecast_s.png", assets.Encoding{}, assets.FormatPNG},
+		{"a/forecast_s.PNG", assets.Encoding{}, assets.FormatPNG},
+		{"a/camera_s.jpg", assets.Encoding{}, assets.FormatJPEG},
+		{"a/camera", assets.Encoding{}, assets.FormatJPEG},
+		{"a/camera_s.jpg", assets.Encoding{Format: assets.FormatPNG}, assets.FormatPNG},
+	}
+	for _, tt := range tests {
+		if got := outputFormat(tt.path, tt.enc); got != tt.want {
+			t.Errorf("outputFormat(%q, %+v) = %q, want %q", tt.path, tt.enc, got, tt.want)
+		}
+	}
+}
+
+func TestSaveEncodedPNGKeepsAlpha(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 40, 40))
+	fill(img, image.Rect(10, 10, 30, 30), color.RGBA{200, 40, 40, 255})
+
+	for _, enc := range []assets.Encoding{{}, {Colors: 16}} {
+		path := filepath.Join(t.TempDir(), "graphic_s.png")
+		if err := saveEncoded(img, path, enc); err != nil {
+			t.Fatalf("saveEncoded(%+v) error: %v", enc, err)
+		}
+
+		got, err := LoadImageForComposite(path)
+		if err != nil {
+			t.Fatalf("failed to load %s: %v", path, err)
+		}
+		if _, _, _, a := got.At(0, 0).RGBA(); a != 0 {
+			t.Errorf("encoding %+v: corner alpha = %d, want transparent", enc, a)
+		}
+		if r, _, _, a := got.At(20, 20).RGBA(); r>>8 != 200 || a>>8 != 255 {
+			t.Errorf("encoding %+v: center = %v, want opaque red", enc, got.At(20, 20))
+		}
+	}
+}
+
+func TestSaveEncodedJPEGQuality(t *testing.T) {
+	// Noisy content so quality changes the file size
+	img := image.NewRGBA(image.Rect(0, 0, 200, 200))
+	for y := 0; y < 200; y++ {
+		for x := 0; x < 200; x++ {
+			img.SetRGBA(x, y, color.RGBA{uint8(x * y), uint8(x + 3*y), uint8(x ^ y), 255})
+		}
+	}
+
+	size := func(quality int) int64 {
+		path := filepath.Join(t.TempDir(), "camera_s.jpg")
+		if err := saveEncoded(img, path, assets.Encoding{Quality: quality}); err != nil {
+			t.Fatalf("saveEncoded(quality %d) error: %v", quality, err)
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return info.Size()
+	}
+
+	if low, high := size(40), size(95); low >= high {
+		t.Errorf("quality 40 = %d bytes, quality 95 = %d bytes; want smaller at lower quality", low, high)
+	}
+}
+
+func TestQuantize(t *testing.T) {
+	// Smooth gradient: many distinct colors
+	gradient := image.NewRGBA(image.Rect(0, 0, 256, 64))
+	for y := 0; y < 64; y++ {
+		for x := 0; x < 256; x++ {
+			gradient.SetRGBA(x, y, color.RGBA{uint8(x), uint8(y * 4), 128, 255})
+		}
+	}
+	for _, n := range []int{2, 16, 256} {
+		if got := len(quantize(gradient, n, false).Palette); got > n {
+			t.Errorf("quantize(gradient, %d) palette has %d colors", n, got)
+		}
+	}
+
+	// Flat graphic with three colors maps exactly
+	flat := testCanvas(30, 10, color.White)
+	fill(flat, image.Rect(0, 0, 10, 10), color.RGBA{255, 0, 0, 255})
+	fill(flat, image.Rect(10, 0, 20, 10), color.RGBA{0, 0, 255, 255})
+	q := quantize(flat, 256, true)
+	if len(q.Palette) != 3 {
+		t.Errorf("quantize(flat) palette has %d colors, want 3", len(q.Palette))
+	}
+	for _, x := range []int{5, 15, 25} {
+		want := color.RGBAModel.Convert(flat.At(x, 5))
+		if got := color.RGBAModel.Convert(q.At(x, 5)); got != want {
+			t.Errorf("quantize(flat) at x=%d = %v, want %v", x, got, want)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package image
+
+import (
+	"image"
+	"math"
+
+	"github.com/trodemaster/weatherdesktop/pkg/assets"
+)
+
+// fitSize returns the size src is scaled to for a fit mode
+// Contain and cover keep the aspect ratio; the result may be smaller (contain)
+// or larger (cover) than target in one dimension
+func fitSize(src, target image.Point, mode assets.FitMode) image.Point {
+	if src.X == 0 || src.Y == 0 {
+		return target
+	}
+	sx := float64(target.X) / float64(src.X)
+	sy := float64(target.Y) / float64(src.Y)
+
+	switch mode {
+	case assets.FitContain:
+		return scaled(src, math.Min(sx, sy))
+	case assets.FitCover:
+		return scaled(src, math.Max(sx, sy))
+	case assets.FitWidth:
+		return image.Point{X: target.X, Y: scaled(src, sx).Y}
+	case assets.FitHeight:
+		return image.Point{X: scaled(src, sy).X, Y: target.Y}
+	}
+
+	return target
+}
+
+// scaled multiplies both dimensions by s, rounding and keeping at least 1 pixel
+func scaled(p image.Point, s float64) image.Point {
+	return image.Point{
+		X: max(int(math.Round(float64(p.X)*s)), 1),
+		Y: max(int(math.Round(float64(p.Y)*s)), 1),
+	}
+}
+
+// alignOffset returns where an inner box goes inside an outer box for a gravity
+// The offset is negative in dimensions where inner is larger than outer
+func alignOffset(outer, inner image.Point, gravity assets.Gravity) image.Point {
+	fx, fy := 0.5, 0.5
+	switch gravity {
+	case assets.GravityTop:
+		fy = 0
+	case assets.GravityBottom:
+		fy = 1
+	case assets.GravityLeft:
+		fx = 0
+	case assets.GravityRight:
+		fx = 1
+	case assets.GravityTopLeft:
+		fx, fy = 0, 0
+	case assets.GravityTopRight:
+		fx, fy = 1, 0
+	case assets.GravityBottomLeft:
+		fx, fy = 0, 1
+	case assets.GravityBottomRight:
+		fx, fy = 1, 1
+	}
+
+	return image.Point{
+		X: int(float64(outer.X-inner.X) * fx),
+		Y: int(float64(outer.Y-inner.Y) * fy),
+	}
+}
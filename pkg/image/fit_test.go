@@ -0,0 +1,133 @@
+package image
+
+import (
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/trodemaster/weatherdesktop/pkg/assets"
+)
+
+func TestFitSize(t *testing.T) {
+	tests := []struct {
+		mode        assets.FitMode
+		src, target image.Point
+		want        image.Point
+	}{
+		{assets.FitStretch, image.Pt(800, 871), image.Pt(855, 930), image.Pt(855, 930)},
+		{assets.FitContain, image.Pt(800, 871), image.Pt(855, 930), image.Pt(854, 930)},
+		{assets.FitContain, image.Pt(1920, 1080), image.Pt(680, 680), image.Pt(680, 383)},
+		{assets.FitCover, image.Pt(1920, 1080), image.Pt(680, 680), image.Pt(1209, 680)},
+		{assets.FitCover, image.Pt(1280, 960), image.Pt(1075, 605), image.Pt(1075, 806)},
+		{assets.FitWidth, image.Pt(1280, 960), image.Pt(640, 100), image.Pt(640, 480)},
+		{assets.FitHeight, image.Pt(1280, 960), image.Pt(100, 480), image.Pt(640, 480)},
+	}
+
+	for _, tt := range tests {
+		if got := fitSize(tt.src, tt.target, tt.mode); got != tt.want {
+			t.Errorf("fitSize(%v, %v, %q) = %v, want %v", tt.src, tt.target, tt.mode, got, tt.want)
+		}
+	}
+}
+
+func TestResizeFit(t *testing.T) {
+	p := &Processor{}
+	red := color.RGBA{255, 0, 0, 255}
+	blue := color.RGBA{0, 0, 255, 255}
+
+	// 200x100 source: left half red, right half blue
+	src := testCanvas(200, 100, red)
+	fill(src, image.Rect(100, 0, 200, 100), blue)
+
+	at := func(img image.Image, x, y int) color.RGBA {
+		b := img.Bounds()
+		return color.RGBAModel.Convert(img.At(b.Min.X+x, b.Min.Y+y)).(color.RGBA)
+	}
+
+	t.Run("contain letterbox", func(t *testing.T) {
+		img := p.resize(src, assets.Asset{TargetSize: image.Pt(100, 100), Fit: assets.FitContain, Letterbox: color.White})
+		if got := img.Bounds().Size(); got != image.Pt(100, 100) {
+			t.Fatalf("size = %v, want 100x100", got)
+		}
+		// 100x50 content centered vertically between white bars
+		if c := at(img, 50, 10); c != (color.RGBA{255, 255, 255, 255}) {
+			t.Errorf("top bar = %v, want white", c)
+		}
+		if c := at(img, 10, 50); c != red {
+			t.Errorf("content = %v, want red", c)
+		}
+	})
+
+	t.Run("contain letterbox top", func(t *testing.T) {
+		img := p.resize(src, assets.Asset{TargetSize: image.Pt(100, 100), Fit: assets.FitContain, Gravity: assets.GravityTop, Letterbox: color.Black})
+		if c := at(img, 10, 10); c != red {
+			t.Errorf("top = %v, want red content", c)
+		}
+		if c := at(img, 10, 90); c != (color.RGBA{0, 0, 0, 255}) {
+			t.Errorf("bottom = %v, want black bar", c)
+		}
+	})
+
+	t.Run("contain transparent", func(t *testing.T) {
+		img := p.resize(src, assets.Asset{TargetSize: image.Pt(100, 100), Fit: assets.FitContain})
+		if got := img.Bounds().Size(); got != image.Pt(100, 50) {
+			t.Errorf("size = %v, want fitted 100x50", got)
+		}
+	})
+
+	t.Run("cover gravity", func(t *testing.T) {
+		left := p.resize(src, assets.Asset{TargetSize: image.Pt(50, 50), Fit: assets.FitCover, Gravity: assets.GravityLeft})
+		right := p.resize(src, assets.Asset{TargetSize: image.Pt(50, 50), Fit: assets.FitCover, Gravity: assets.GravityRight})
+		if got := left.Bounds().Size(); got != image.Pt(50, 50) {
+			t.Fatalf("size = %v, want 50x50", got)
+		}
+		if c := at(left, 25, 25); c != red {
+			t.Errorf("left gravity = %v, want red", c)
+		}
+		if c := at(right, 25, 25); c != blue {
+			t.Errorf("right gravity = %v, want blue", c)
+		}
+	})
+}
+
+func TestCompositeLayerBox(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "layer.png")
+	layer := testCanvas(100, 50, color.RGBA{255, 0, 0, 255})
+	writeTestPNG(t, path, layer)
+
+	c := &Compositor{}
+	canvas := testCanvas(300, 300, color.White)
+	err := c.compositeLayer(canvas, assets.CompositeLayer{
+		ImagePath: path,
+		Position:  image.Pt(50, 50),
+		Size:      image.Pt(100, 100),
+	})
+	if err != nil {
+		t.Fatalf("compositeLayer() error: %v", err)
+	}
+
+	// Centered vertically: rows 75-124 red, the rest of the box untouched
+	if c := canvas.RGBAAt(60, 80); c != (color.RGBA{255, 0, 0, 255}) {
+		t.Errorf("inside = %v, want red", c)
+	}
+	if c := canvas.RGBAAt(60, 60); c != (color.RGBA{255, 255, 255, 255}) {
+		t.Errorf("letterbox = %v, want canvas white", c)
+	}
+}
+
+// writeTestPNG saves img as a PNG for tests that go through the file system
+func writeTestPNG(t *testing.T, path string, img image.Image) {
+	t.Helper()
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("failed to create %s: %v", path, err)
+	}
+	defer f.Close()
+	if err := png.Encode(f, img); err != nil {
+		t.Fatalf("failed to encode %s: %v", path, err)
+	}
+}
@@ -0,0 +1,209 @@
+package image
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"log"
+	"time"
+
+	"github.com/trodemaster/weatherdesktop/pkg/assets"
+	"github.com/trodemaster/weatherdesktop/pkg/history"
+	"github.com/trodemaster/weatherdesktop/pkg/parser"
+	"github.com/trodemaster/weatherdesktop/pkg/results"
+	"golang.org/x/image/font"
+	"golang.org/x/image/math/fixed"
+)
+
+// HUD strip layout and colors (light on the dark strip)
+const hudPadding = 12
+
+var (
+	hudBackground = color.RGBA{0, 0, 0, 150}
+	hudDivider    = color.RGBA{255, 255, 255, 90}
+	hudText       = color.RGBA{255, 255, 255, 255}
+	hudAmber      = color.RGBA{255, 190, 70, 255}
+	hudRed        = color.RGBA{255, 105, 97, 255}
+	hudGray       = color.RGBA{170, 170, 170, 255}
+)
+
+// hudSegment is one item of the HUD strip
+// short replaces text when the full strip doesn't fit its region
+type hudSegment struct {
+	text  string
+	short string
+	col   color.Color
+}
+
+// sourceCounts summarizes the latest fetch of every source
+type sourceCounts struct {
+	fresh, stale, failed int
+}
+
+// drawHUD draws the status strip: render time, source freshness and pass status
+func (c *Compositor) drawHUD(canvas *image.RGBA, hud assets.HUD) {
+	runResults, err := results.Load(c.manager.GetRunResultsPath())
+	if err != nil {
+		log.Printf("Warning: %v", err)
+	}
+
+	now := c.clock()
+	segments := []hudSegment{renderTimeSegment(now)}
+	if len(runResults.Sources) > 0 {
+		counts := countSources(runResults.Sources, now, hud.StaleAfter)
+		segments = append(segments, sourcesSegment(counts))
+		log.Printf("HUD sources: %d fresh, %d stale, %d failed", counts.fresh, counts.stale, counts.failed)
+	} else {
+		segments = append(segments, hudSegment{text: "Sources: no fetch results", short: "No sources", col: hudGray})
+	}
+	if len(c.passes) > 0 {
+		segments = append(segments, passSegment(c.passes[0], now))
+	}
+
+	drawHUDStrip(canvas, hud, segments, c.labelFace())
+}
+
+// countSources sorts sources into fresh, stale (data older than staleAfter) and failed
+func countSources(sources []results.Source, now time.Time, staleAfter time.Duration) sourceCounts {
+	var counts sourceCounts
+	for _, src := range sources {
+		switch {
+		case !src.OK:
+			counts.failed++
+		case staleAfter > 0 && now.Sub(src.Updated) > staleAfter:
+			counts.stale++
+		default:
+			counts.fresh++
+		}
+	}
+	return counts
+}
+
+// renderTimeSegment shows when the wallpaper was rendered, in Pacific time
+func renderTimeSegment(now time.Time) hudSegment {
+	local := now.In(parser.Pacific)
+	return hudSegment{
+		text:  "Rendered " + local.Format("Mon Jan 2 3:04 PM"),
+		short: local.Format("3:04 PM"),
+		col:   hudText,
+	}
+}
+
+// sourcesSegment shows the source counts, colored by the worst
+func sourcesSegment(counts sourceCounts) hudSegment {
+	col := color.Color(hudText)
+	switch {
+	case counts.failed > 0:
+		col = hudRed
+	case counts.stale > 0:
+		col = hudAmber
+	}
+	short := fmt.Sprintf("%d ok", counts.fresh)
+	if counts.stale > 0 {
+		short += fmt.Sprintf(", %d stale", counts.stale)
+	}
+	if counts.failed > 0 {
+		short += fmt.Sprintf(", %d failed", counts.failed)
+	}
+	return hudSegment{
+		text:  fmt.Sprintf("Sources: %d fresh, %d stale, %d failed", counts.fresh, counts.stale, counts.failed),
+		short: short,
+		col:   col,
+	}
+}
+
+// passSegment shows a pass's restrictions, with the closure duration when closed
+func passSegment(pr parser.PassResult, now time.Time) hudSegment {
+	if pr.Status == nil {
+		return hudSegment{text: pr.Pass.Name + ": Unknown", short: "Unknown", col: hudGray}
+	}
+
+	status := pr.Status
+	east, west := parser.SummarizeRestriction(status.East), parser.SummarizeRestriction(status.West)
+	summary := east
+	if east != west {
+		summary = "E " + east + ", W " + west
+	}
+	if status.IsClosed && !status.ClosedSince.IsZero() {
+		summary += " (" + history.FormatDuration(now.Sub(status.ClosedSince)) + ")"
+	}
+
+	col := color.Color(hudAmber)
+	switch {
+	case east == "Closed" || west == "Closed":
+		col = hudRed
+	case east == "No restrictions" && west == "No restrictions":
+		col = hudText
+	case east == "Unknown" && west == "Unknown":
+		col = hudGray
+	}
+	return hudSegment{text: pr.Pass.Name + ": " + summary, short: summary, col: col}
+}
+
+// drawHUDStrip fills the HUD region and draws the segments left to right between dividers
+// Falls back to short texts, then drops trailing segments, to fit the region
+func drawHUDStrip(canvas *image.RGBA, hud assets.HUD, segments []hudSegment, face font.Face) {
+	region := hud.Region.Intersect(canvas.Bounds())
+	if region.Empty() {
+		return
+	}
+	dst := canvas.SubImage(region).(*image.RGBA) // Clip text that still doesn't fit
+
+	bg := hud.Background
+	if bg == nil {
+		bg = hudBackground
+	}
+	draw.Draw(dst, region, image.NewUniform(bg), image.Point{}, draw.Over)
+
+	d := &font.Drawer{Dst: dst, Face: face}
+	texts := hudTexts(d, segments, region.Dx())
+
+	metrics := face.Metrics()
+	textHeight := (metrics.Ascent + metrics.Descent).Ceil()
+	baseline := fixed.I(region.Min.Y+(region.Dy()-textHeight)/2) + metrics.Ascent
+
+	x := region.Min.X + hudPadding
+	for i, text := range texts {
+		if i > 0 {
+			draw.Draw(dst, image.Rect(x, region.Min.Y+hudPadding/2, x+1, region.Max.Y-hudPadding/2), image.NewUniform(hudDivider), image.Point{}, draw.Over)
+			x += 1 + hudPadding
+		}
+		d.Src = image.NewUniform(segments[i].col)
+		d.Dot = fixed.Point26_6{X: fixed.I(x), Y: baseline}
+		d.DrawString(text)
+		x += d.MeasureString(text).Ceil() + hudPadding
+	}
+}
+
+// hudTexts picks the segment texts that fit width: all full, all short, or fewer short ones
+func hudTexts(d *font.Drawer, segments []hudSegment, width int) []string {
+	fits := func(texts []string) bool {
+		w := hudPadding
+		for i, text := range texts {
+			if i > 0 {
+				w += 1 + hudPadding
+			}
+			w += d.MeasureString(text).Ceil() + hudPadding
+		}
+		return w <= width
+	}
+
+	full := make([]string, len(segments))
+	short := make([]string, len(segments))
+	for i, seg := range segments {
+		full[i], short[i] = seg.text, seg.short
+		if short[i] == "" {
+			short[i] = seg.text
+		}
+	}
+	if fits(full) {
+		return full
+	}
+	for n := len(short); n > 1; n-- {
+		if fits(short[:n]) {
+			return short[:n]
+		}
+	}
+	return short[:min(1, len(short))]
+}
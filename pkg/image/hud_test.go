@@ -0,0 +1,114 @@
+package image
+
+import (
+	"image"
+	"testing"
+	"time"
+
+	"github.com/trodemaster/weatherdesktop/pkg/assets"
+	"github.com/trodemaster/weatherdesktop/pkg/parser"
+	"github.com/trodemaster/weatherdesktop/pkg/results"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+)
+
+func TestCountSources(t *testing.T) {
+	now := time.Date(2025, 1, 15, 16, 0, 0, 0, parser.Pacific)
+	sources := []results.Source{
+		{Name: "cam", OK: true, Updated: now.Add(-5 * time.Minute)},
+		{Name: "frozen cam", OK: true, Updated: now.Add(-3 * time.Hour)},
+		{Name: "forecast", OK: true, Updated: now.Add(-20 * time.Minute)},
+		{Name: "down", OK: false, Updated: now.Add(-5 * time.Minute)},
+	}
+
+	want := sourceCounts{fresh: 2, stale: 1, failed: 1}
+	if got := countSources(sources, now, time.Hour); got != want {
+		t.Errorf("countSources() = %+v, want %+v", got, want)
+	}
+
+	// No staleness limit: only failures count against a source
+	want = sourceCounts{fresh: 3, failed: 1}
+	if got := countSources(sources, now, 0); got != want {
+		t.Errorf("countSources(no limit) = %+v, want %+v", got, want)
+	}
+}
+
+func TestPassSegment(t *testing.T) {
+	now := time.Date(2025, 1, 15, 16, 0, 0, 0, parser.Pacific)
+	pass := assets.PassDefinition{Name: "Stevens Pass"}
+
+	tests := []struct {
+		name   string
+		status *parser.PassStatus
+		want   string
+	}{
+		{"unknown", nil, "Stevens Pass: Unknown"},
+		{"open", &parser.PassStatus{East: "No restrictions", West: "No restrictions"}, "Stevens Pass: No restrictions"},
+		{"mixed", &parser.PassStatus{East: "Traction Tires Required", West: "No restrictions"}, "Stevens Pass: E Traction tires, W No restrictions"},
+		{"closed", &parser.PassStatus{East: "Pass Closed", West: "Pass Closed", IsClosed: true, ClosedSince: now.Add(-200 * time.Minute)}, "Stevens Pass: Closed (3h 20m)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := passSegment(parser.PassResult{Pass: pass, Status: tt.status}, now).text; got != tt.want {
+				t.Errorf("passSegment() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHUDTexts(t *testing.T) {
+	d := &font.Drawer{Face: basicfont.Face7x13}
+	segments := []hudSegment{
+		{text: "Rendered Wed Jan 15 4:00 PM", short: "4:00 PM"},
+		{text: "Sources: 18 fresh, 2 stale, 1 failed", short: "18 ok, 2 stale, 1 failed"},
+		{text: "Stevens Pass: No restrictions", short: "No restrictions"},
+	}
+
+	tests := []struct {
+		width int
+		want  []string
+	}{
+		{1000, []string{"Rendered Wed Jan 15 4:00 PM", "Sources: 18 fresh, 2 stale, 1 failed", "Stevens Pass: No restrictions"}},
+		{400, []string{"4:00 PM", "18 ok, 2 stale, 1 failed", "No restrictions"}},
+		{280, []string{"4:00 PM", "18 ok, 2 stale, 1 failed"}},
+		{10, []string{"4:00 PM"}},
+	}
+
+	for _, tt := range tests {
+		got := hudTexts(d, segments, tt.width)
+		if len(got) != len(tt.want) {
+			t.Errorf("hudTexts(width %d) = %q, want %q", tt.width, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("hudTexts(width %d) = %q, want %q", tt.width, got, tt.want)
+				break
+			}
+		}
+	}
+}
+
+func TestHUDGolden(t *testing.T) {
+	now := time.Date(2025, 1, 15, 16, 0, 0, 0, parser.Pacific)
+	segments := []hudSegment{
+		renderTimeSegment(now),
+		sourcesSegment(sourceCounts{fresh: 18, stale: 2}),
+		passSegment(parser.PassResult{
+			Pass:   assets.PassDefinition{Name: "Stevens Pass"},
+			Status: &parser.PassStatus{East: "Pass Closed", West: "Traction Tires Required", IsClosed: true},
+		}, now),
+	}
+
+	canvas := checkerCanvas(840, 60)
+	hud := assets.HUD{Region: image.Rect(10, 15, 830, 45)}
+	drawHUDStrip(canvas, hud, segments, basicfont.Face7x13)
+	checkGolden(t, "hud_strip", canvas)
+
+	// Too narrow for the full texts
+	canvas = checkerCanvas(300, 60)
+	hud.Region = image.Rect(10, 15, 290, 45)
+	drawHUDStrip(canvas, hud, segments, basicfont.Face7x13)
+	checkGolden(t, "hud_strip_short", canvas)
+}
@@ -0,0 +1,128 @@
+package image
+
+import (
+	"image"
+	"image/draw"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/trodemaster/weatherdesktop/pkg/assets"
+	"github.com/trodemaster/weatherdesktop/pkg/parser"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+)
+
+// Night handling thresholds
+const (
+	nightLuminance = 40   // Mean luma (0-255) below which a frame counts as dark
+	nightDimFactor = 0.35 // Brightness kept by NightDim
+	maxLumaSamples = 10000
+)
+
+// nightFrame applies a layer's night policy to its image and returns what to composite
+// Returns nil if the layer is hidden. Bright daylight frames are kept as the layer's last light
+func (c *Compositor) nightFrame(layer assets.CompositeLayer, img image.Image) image.Image {
+	name := filepath.Base(layer.ImagePath)
+	lastLight := filepath.Join(c.manager.GetLastLightDir(), name)
+	luma := meanLuma(img)
+
+	if c.daylight || luma >= nightLuminance {
+		if c.daylight && luma >= nightLuminance {
+			c.saveLastLight(img, lastLight)
+		}
+		return img
+	}
+
+	log.Printf("%s is dark (mean luminance %.0f), night mode %s", name, luma, layer.Night)
+
+	switch layer.Night {
+	case assets.NightHide:
+		return nil
+
+	case assets.NightLastLight:
+		frame, err := LoadImageForComposite(lastLight)
+		if err == nil {
+			var saved string
+			if info, err := os.Stat(lastLight); err == nil {
+				saved = " " + info.ModTime().In(parser.Pacific).Format("3:04 PM")
+			}
+			out := toRGBA(frame)
+			c.drawBadge(out, "Last light"+saved)
+			return out
+		}
+		log.Printf("Warning: No last light frame for %s, dimming instead: %v", name, err)
+	}
+
+	out := toRGBA(img)
+	dim(out, nightDimFactor)
+	c.drawBadge(out, "Night")
+	return out
+}
+
+// saveLastLight keeps a copy of a daylight frame for the night
+func (c *Compositor) saveLastLight(img image.Image, path string) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		log.Printf("Warning: Failed to create last light directory: %v", err)
+		return
+	}
+	if err := saveEncoded(img, path, assets.Encoding{}); err != nil {
+		log.Printf("Warning: Failed to save last light frame %s: %v", path, err)
+	}
+}
+
+// drawBadge labels the bottom-left corner of img on a translucent plate
+func (c *Compositor) drawBadge(img *image.RGBA, text string) {
+	drawLabel(img, img.Rect, text, c.labelFace(), assets.CaptionBottomLeft, assets.CaptionBacked)
+}
+
+// labelFace returns the font for layer labels: the pass status face, or basicfont
+func (c *Compositor) labelFace() font.Face {
+	if c.text == nil {
+		c.text = NewTextRenderer()
+	}
+	if c.text.boldFace != nil {
+		return c.text.boldFace
+	}
+	return basicfont.Face7x13
+}
+
+// meanLuma returns the mean luminance (0-255) of img, sampled on a grid
+func meanLuma(img image.Image) float64 {
+	b := img.Bounds()
+	if b.Empty() {
+		return 0
+	}
+
+	step := 1
+	for (b.Dx()/step)*(b.Dy()/step) > maxLumaSamples {
+		step++
+	}
+
+	var sum, n float64
+	for y := b.Min.Y; y < b.Max.Y; y += step {
+		for x := b.Min.X; x < b.Max.X; x += step {
+			r, g, bl, _ := img.At(x, y).RGBA()
+			sum += float64(lumaOf(uint8(r>>8), uint8(g>>8), uint8(bl>>8)))
+			n++
+		}
+	}
+	return sum / n
+}
+
+// dim scales the color of every pixel by factor
+func dim(img *image.RGBA, factor float64) {
+	for i := 0; i < len(img.Pix); i += 4 {
+		img.Pix[i] = uint8(float64(img.Pix[i]) * factor)
+		img.Pix[i+1] = uint8(float64(img.Pix[i+1]) * factor)
+		img.Pix[i+2] = uint8(float64(img.Pix[i+2]) * factor)
+	}
+}
+
+// toRGBA returns a copy of img as RGBA with its origin at (0, 0)
+func toRGBA(img image.Image) *image.RGBA {
+	b := img.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(dst, dst.Bounds(), img, b.Min, draw.Src)
+	return dst
+}
@@ -0,0 +1,92 @@
+package image
+
+import (
+	"image"
+	"image/color"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/trodemaster/weatherdesktop/pkg/assets"
+)
+
+func TestMeanLuma(t *testing.T) {
+	if got := meanLuma(testCanvas(200, 100, color.Black)); got != 0 {
+		t.Errorf("meanLuma(black) = %v, want 0", got)
+	}
+	if got := meanLuma(testCanvas(200, 100, color.White)); got != 255 {
+		t.Errorf("meanLuma(white) = %v, want 255", got)
+	}
+
+	half := testCanvas(200, 100, color.Black)
+	fill(half, image.Rect(0, 0, 100, 100), color.White)
+	if got := meanLuma(half); got < 120 || got > 135 {
+		t.Errorf("meanLuma(half white) = %v, want about 128", got)
+	}
+}
+
+func TestNightFrame(t *testing.T) {
+	mgr := assets.NewManager(t.TempDir())
+	c := &Compositor{manager: mgr}
+
+	day := testCanvas(400, 200, color.RGBA{120, 160, 200, 255})
+	dark := testCanvas(400, 200, color.RGBA{10, 10, 14, 255})
+	lit := testCanvas(400, 200, color.RGBA{90, 90, 80, 255}) // Night skiing lights
+
+	layer := func(mode assets.NightMode) assets.CompositeLayer {
+		return assets.CompositeLayer{ImagePath: filepath.Join(mgr.AssetsDir, "cam_s.jpg"), Night: mode}
+	}
+	lastLight := filepath.Join(mgr.GetLastLightDir(), "cam_s.jpg")
+
+	// Daylight keeps the frame and saves it as last light
+	c.daylight = true
+	if got := c.nightFrame(layer(assets.NightLastLight), day); got != image.Image(day) {
+		t.Error("daylight: frame replaced")
+	}
+	if _, err := os.Stat(lastLight); err != nil {
+		t.Fatalf("daylight: last light not saved: %v", err)
+	}
+
+	// A dark frame in daylight (storm, fog) is still current
+	if got := c.nightFrame(layer(assets.NightHide), dark); got == nil {
+		t.Error("daylight dark frame: hidden")
+	}
+
+	c.daylight = false
+
+	if got := c.nightFrame(layer(assets.NightDim), lit); got != image.Image(lit) {
+		t.Error("night, lit frame: replaced")
+	}
+	if got := c.nightFrame(layer(assets.NightHide), dark); got != nil {
+		t.Error("night hide: layer shown")
+	}
+
+	dimmed := c.nightFrame(layer(assets.NightDim), dark)
+	if dimmed == nil {
+		t.Fatal("night dim: layer hidden")
+	}
+	if r, _, _, _ := dimmed.At(300, 20).RGBA(); r>>8 > 4 {
+		t.Errorf("night dim: red = %d, want <= 4", r>>8)
+	}
+
+	// Last light: the saved daylight frame (away from the badge), JPEG-close to the original
+	last := c.nightFrame(layer(assets.NightLastLight), dark)
+	if last == nil {
+		t.Fatal("night last light: layer hidden")
+	}
+	got := color.RGBAModel.Convert(last.At(300, 20)).(color.RGBA)
+	if absDiff(got.R, 120) > 6 || absDiff(got.G, 160) > 6 || absDiff(got.B, 200) > 6 {
+		t.Errorf("night last light = %v, want the daylight frame", got)
+	}
+	// Badge plate darkens the bottom-left corner
+	if corner := color.RGBAModel.Convert(last.At(labelMargin+2, 200-labelMargin-2)).(color.RGBA); corner.B > 120 {
+		t.Errorf("night last light: no badge plate (%v)", corner)
+	}
+
+	// Without a saved frame, last light falls back to dimming
+	os.Remove(lastLight)
+	fallback := c.nightFrame(layer(assets.NightLastLight), dark)
+	if r, _, _, _ := fallback.At(300, 20).RGBA(); r>>8 > 4 {
+		t.Errorf("night last light fallback: red = %d, want dimmed", r>>8)
+	}
+}
@@ -0,0 +1,154 @@
+package image
+
+import (
+	"image"
+	"log"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/trodemaster/weatherdesktop/pkg/assets"
+)
+
+// Memory budget defaults
+const (
+	DefaultMemoryBudget = 1 << 30 // Used when the container has no memory limit
+	bytesPerPixel       = 4       // Decoded images and scale buffers are RGBA (or close to it)
+)
+
+// cgroupMemoryLimits are the cgroup v2 and v1 memory limit files, in lookup order
+var cgroupMemoryLimits = []string{
+	"/sys/fs/cgroup/memory.max",
+	"/sys/fs/cgroup/memory/memory.limit_in_bytes",
+}
+
+// ProcessorOptions configures a Processor
+type ProcessorOptions struct {
+	Workers      int   // Assets processed concurrently (0 = GOMAXPROCS)
+	MemoryBudget int64 // Bytes of decoded pixels in flight (0 = half the container limit)
+	Debug        bool  // Log per-asset timings
+}
+
+// memoryBudget bounds the estimated bytes of images being processed at once
+// An asset larger than the whole budget still runs, but only on its own
+type memoryBudget struct {
+	mu    sync.Mutex
+	cond  *sync.Cond
+	limit int64
+	used  int64
+}
+
+func newMemoryBudget(limit int64) *memoryBudget {
+	b := &memoryBudget{limit: limit}
+	b.cond = sync.NewCond(&b.mu)
+	return b
+}
+
+// acquire blocks until n bytes fit in the budget
+func (b *memoryBudget) acquire(n int64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for b.used > 0 && b.used+n > b.limit {
+		b.cond.Wait()
+	}
+	b.used += n
+}
+
+func (b *memoryBudget) release(n int64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.used -= n
+	b.cond.Broadcast()
+}
+
+// processAll processes assets over a bounded worker pool
+// Failures are logged and do not stop the other assets
+func (p *Processor) processAll(cropAssets []assets.Asset) {
+	workers := p.workers
+	if workers > len(cropAssets) {
+		workers = len(cropAssets)
+	}
+	budget := newMemoryBudget(p.memoryBudget)
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				asset := cropAssets[i]
+				cost := assetCost(asset)
+
+				budget.acquire(cost)
+				start := time.Now()
+				log.Printf("Processing %s", asset.Name)
+				if err := p.processAsset(asset); err != nil {
+					log.Printf("Failed to process %s: %v", asset.Name, err)
+				} else if p.debug {
+					log.Printf("Processed %s in %v (~%d MB)", asset.Name, time.Since(start).Round(time.Millisecond), cost>>20)
+				}
+				budget.release(cost)
+			}
+		}()
+	}
+
+	for i := range cropAssets {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+}
+
+// assetCost estimates the peak memory for processing an asset from its image header:
+// the decoded source plus the scaled output
+func assetCost(asset assets.Asset) int64 {
+	f, err := os.Open(asset.InputPath)
+	if err != nil {
+		return 0 // processAsset reports the error
+	}
+	defer f.Close()
+
+	cfg, _, err := image.DecodeConfig(f)
+	if err != nil {
+		return 0
+	}
+
+	src := image.Point{X: cfg.Width, Y: cfg.Height}
+	crop := asset.CropRect.Intersect(image.Rectangle{Max: src}).Size()
+	if crop.X == 0 || crop.Y == 0 {
+		crop = src
+	}
+	out := crop
+	if asset.TargetSize != (image.Point{}) {
+		out = fitSize(crop, asset.TargetSize, asset.Fit)
+	}
+
+	return int64(src.X*src.Y+out.X*out.Y) * bytesPerPixel
+}
+
+// defaultWorkers returns the worker count when none is configured
+func defaultWorkers() int {
+	return runtime.GOMAXPROCS(0)
+}
+
+// defaultMemoryBudget returns half the container memory limit, or DefaultMemoryBudget
+// when there is no limit (or it cannot be read)
+func defaultMemoryBudget() int64 {
+	for _, path := range cgroupMemoryLimits {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		limit, err := strconv.ParseInt(strings.TrimSpace(string(data)), 10, 64)
+		if err != nil || limit <= 0 || limit > 1<<50 {
+			// "max" (v2) or a huge sentinel (v1) means unlimited
+			return DefaultMemoryBudget
+		}
+		return limit / 2
+	}
+	return DefaultMemoryBudget
+}
@@ -0,0 +1,124 @@
+package image
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/trodemaster/weatherdesktop/pkg/assets"
+)
+
+func TestMemoryBudget(t *testing.T) {
+	budget := newMemoryBudget(100)
+
+	var running, peak atomic.Int64
+	var wg sync.WaitGroup
+	for _, cost := range []int64{60, 60, 30, 250} {
+		wg.Add(1)
+		go func(cost int64) {
+			defer wg.Done()
+			budget.acquire(cost)
+			n := running.Add(cost)
+			for {
+				p := peak.Load()
+				if n <= p || peak.CompareAndSwap(p, n) {
+					break
+				}
+			}
+			time.Sleep(10 * time.Millisecond)
+			running.Add(-cost)
+			budget.release(cost)
+		}(cost)
+	}
+	wg.Wait()
+
+	// The 250 job exceeds the budget on its own, so it may only run alone
+	if p := peak.Load(); p > 100 && p != 250 {
+		t.Errorf("peak in-flight cost = %d, want <= 100 (or the oversized job alone)", p)
+	}
+}
+
+func TestAssetCost(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "source.png")
+	writeTestPNG(t, path, testCanvas(400, 300, color.White))
+
+	asset := assets.Asset{InputPath: path, CropRect: image.Rect(0, 0, 200, 300), TargetSize: image.Pt(100, 150)}
+	if got, want := assetCost(asset), int64(400*300+100*150)*bytesPerPixel; got != want {
+		t.Errorf("assetCost() = %d, want %d", got, want)
+	}
+
+	asset.InputPath = filepath.Join(t.TempDir(), "missing.png")
+	if got := assetCost(asset); got != 0 {
+		t.Errorf("assetCost() of missing file = %d, want 0", got)
+	}
+}
+
+// writeBenchAssets creates synthetic source images matching the configured crop assets
+// (same files, formats and at least the crop rect's extent)
+func writeBenchAssets(b *testing.B, mgr *assets.Manager) {
+	b.Helper()
+	if err := os.MkdirAll(mgr.AssetsDir, 0755); err != nil {
+		b.Fatal(err)
+	}
+
+	for _, asset := range mgr.GetCropAssets() {
+		size := asset.CropRect.Max
+		img := image.NewRGBA(image.Rect(0, 0, size.X, size.Y))
+		for y := 0; y < size.Y; y++ {
+			for x := 0; x < size.X; x++ {
+				// Gradient with some texture so encoders and scalers do real work
+				v := uint8((x*7 + y*13 + (x*y)%31) % 256)
+				img.SetRGBA(x, y, color.RGBA{v, uint8(x), uint8(y), 255})
+			}
+		}
+
+		f, err := os.Create(asset.InputPath)
+		if err != nil {
+			b.Fatal(err)
+		}
+		if filepath.Ext(asset.InputPath) == ".png" {
+			err = png.Encode(f, img)
+		} else {
+			err = jpeg.Encode(f, img, &jpeg.Options{Quality: 90})
+		}
+		f.Close()
+		if err != nil {
+			b.Fatalf("failed to write %s: %v", asset.InputPath, err)
+		}
+	}
+}
+
+// BenchmarkProcessAll compares the crop phase on the current asset set
+// sequentially, with fixed worker counts and with the default (GOMAXPROCS)
+func BenchmarkProcessAll(b *testing.B) {
+	mgr := assets.NewManager(b.TempDir())
+	writeBenchAssets(b, mgr)
+
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+
+	for _, workers := range []int{1, 2, 4, 0} {
+		name := fmt.Sprintf("workers=%d", workers)
+		if workers == 0 {
+			name = fmt.Sprintf("workers=default(%d)", runtime.GOMAXPROCS(0))
+		}
+		b.Run(name, func(b *testing.B) {
+			p := NewProcessorWithOptions(mgr, ProcessorOptions{Workers: workers})
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				p.ProcessAll()
+			}
+		})
+	}
+}
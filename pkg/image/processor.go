@@ -3,10 +3,10 @@ package image
 import (
 	"fmt"
 	"image"
-	"image/jpeg"
 	_ "image/png"
 	"log"
 	"os"
+	"time"
 
 	"github.com/trodemaster/weatherdesktop/pkg/assets"
 	"golang.org/x/image/draw"
@@ -14,28 +14,41 @@ import (
 
 // Processor handles image cropping and resizing
 type Processor struct {
-	manager *assets.Manager
+	manager      *assets.Manager
+	workers      int
+	memoryBudget int64
+	debug        bool
 }
 
 // NewProcessor creates a new image processor
 func NewProcessor(manager *assets.Manager) *Processor {
+	return NewProcessorWithOptions(manager, ProcessorOptions{})
+}
+
+// NewProcessorWithOptions creates a new image processor with explicit options
+func NewProcessorWithOptions(manager *assets.Manager, opts ProcessorOptions) *Processor {
+	if opts.Workers <= 0 {
+		opts.Workers = defaultWorkers()
+	}
+	if opts.MemoryBudget <= 0 {
+		opts.MemoryBudget = defaultMemoryBudget()
+	}
 	return &Processor{
-		manager: manager,
+		manager:      manager,
+		workers:      opts.Workers,
+		memoryBudget: opts.MemoryBudget,
+		debug:        opts.Debug,
 	}
 }
 
 // ProcessAll crops and resizes all configured assets
+// Assets are processed concurrently; one failing does not stop the others
 func (p *Processor) ProcessAll() error {
 	cropAssets := p.manager.GetCropAssets()
 	
-	for _, asset := range cropAssets {
-		log.Printf("Processing %s", asset.Name)
-		
-		if err := p.processAsset(asset); err != nil {
-			log.Printf("Failed to process %s: %v", asset.Name, err)
-			// Continue with other assets even if one fails
-		}
-	}
+	start := time.Now()
+	p.processAll(cropAssets)
+	log.Printf("Processed %d assets in %v (%d workers)", len(cropAssets), time.Since(start).Round(time.Millisecond), p.workers)
 	
 	return nil
 }
@@ -48,14 +61,24 @@ func (p *Processor) processAsset(asset assets.Asset) error {
 		return fmt.Errorf("failed to load image: %w", err)
 	}
 
-	// Crop to specified rectangle
-	img = p.crop(img, asset.CropRect)
+	// Crop to specified rectangle (or the detected content)
+	cropRect := asset.CropRect
+	if asset.AutoCrop != nil {
+		cropRect = p.detectCrop(img, asset)
+	}
+	img = p.crop(img, cropRect)
 
 	// Resize to target size
-	img = p.resize(img, asset.TargetSize)
+	img = p.resize(img, asset)
+
+	// Tone, color and sharpening steps
+	img, err = p.adjust(img, asset.Adjust)
+	if err != nil {
+		return fmt.Errorf("failed to adjust image: %w", err)
+	}
 
 	// Save processed image
-	if err := p.saveImage(img, asset.OutputPath); err != nil {
+	if err := saveEncoded(img, asset.OutputPath, asset.Encoding); err != nil {
 		return fmt.Errorf("failed to save image: %w", err)
 	}
 
@@ -99,43 +122,41 @@ func (p *Processor) crop(img image.Image, cropRect image.Rectangle) image.Image
 	return cropped
 }
 
-// resize resizes an image to the target size
-func (p *Processor) resize(img image.Image, targetSize image.Point) image.Image {
+// resize scales an image to the asset's target size using its fit mode
+func (p *Processor) resize(img image.Image, asset assets.Asset) image.Image {
 	bounds := img.Bounds()
-	newWidth := targetSize.X
-	newHeight := targetSize.Y
+	targetSize := asset.TargetSize
 
 	// If target size is zero, return original image
-	if newWidth == 0 && newHeight == 0 {
+	if targetSize.X == 0 && targetSize.Y == 0 {
 		return img
 	}
 
-	// Create destination image
-	dst := image.NewRGBA(image.Rect(0, 0, newWidth, newHeight))
-
 	// Use CatmullRom interpolation for high-quality resizing
+	size := fitSize(bounds.Size(), targetSize, asset.Fit)
+	dst := image.NewRGBA(image.Rect(0, 0, size.X, size.Y))
 	draw.CatmullRom.Scale(dst, dst.Bounds(), img, bounds, draw.Over, nil)
 
-	return dst
-}
-
-// saveImage saves an image to file as JPEG
-func (p *Processor) saveImage(img image.Image, path string) error {
-	f, err := os.Create(path)
-	if err != nil {
-		return fmt.Errorf("failed to create file: %w", err)
-	}
-	defer f.Close()
-	
-	// Determine output format based on extension
-	// For now, default to JPEG with quality 90
-	opts := &jpeg.Options{Quality: 90}
-	
-	if err := jpeg.Encode(f, img, opts); err != nil {
-		return fmt.Errorf("failed to encode image: %w", err)
+	switch asset.Fit {
+	case assets.FitContain:
+		// Without a letterbox color the fitted image is saved as is;
+		// the compositor aligns it inside the layer's Size
+		if asset.Letterbox == nil || size == targetSize {
+			return dst
+		}
+		boxed := image.NewRGBA(image.Rect(0, 0, targetSize.X, targetSize.Y))
+		draw.Draw(boxed, boxed.Bounds(), image.NewUniform(asset.Letterbox), image.Point{}, draw.Src)
+		offset := alignOffset(targetSize, size, asset.Gravity)
+		draw.Draw(boxed, dst.Bounds().Add(offset), dst, image.Point{}, draw.Over)
+		return boxed
+
+	case assets.FitCover:
+		// Trim the overflow, keeping the part at Gravity
+		origin := alignOffset(targetSize, size, asset.Gravity).Mul(-1)
+		return dst.SubImage(image.Rectangle{Min: origin, Max: origin.Add(targetSize)})
 	}
-	
-	return nil
+
+	return dst
 }
 
 // LoadImageForComposite loads an image for compositing (with error handling)
@@ -4,11 +4,14 @@ import (
 	"fmt"
 	"image"
 	"image/color"
+	"image/draw"
 	"image/png"
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
+	"github.com/trodemaster/weatherdesktop/pkg/history"
 	"github.com/trodemaster/weatherdesktop/pkg/parser"
 	"golang.org/x/image/font"
 	"golang.org/x/image/font/basicfont"
@@ -19,6 +22,7 @@ import (
 // TextRenderer renders text to images
 type TextRenderer struct {
 	boldFace font.Face
+	bodyFace font.Face // Smaller face for panel body text
 }
 
 // NewTextRenderer creates a new text renderer
@@ -35,6 +39,11 @@ func NewTextRenderer() *TextRenderer {
 		tr.boldFace = nil
 	}
 	
+	// Body text for text panels (only the bold Roboto is bundled)
+	if face, err := loadFont("fonts/Roboto-Bold.ttf", 17); err == nil {
+		tr.bodyFace = face
+	}
+	
 	return tr
 }
 
@@ -248,6 +257,9 @@ func (tr *TextRenderer) RenderPassStatus(status *parser.PassStatus, width, heigh
 	isWestClosed := strings.Contains(status.West, "Closed")
 	
 	title := "Stevens Pass Status"
+	if status.Name != "" {
+		title = status.Name + " Status"
+	}
 	eastLabel := "East:"
 	eastStatus := "Open"
 	if isEastClosed {
@@ -259,6 +271,15 @@ func (tr *TextRenderer) RenderPassStatus(status *parser.PassStatus, width, heigh
 		westStatus = "Closed"
 	}
 	
+	closureLines := closureDetails(status, time.Now())
+	
+	// Show the original reopening text first, without repeating it in the conditions
+	conditions := status.Conditions
+	if status.Reopening != nil {
+		rest := strings.TrimSpace(strings.Replace(conditions, status.Reopening.Text, "", 1))
+		conditions = strings.TrimSpace(status.Reopening.Text + " " + rest)
+	}
+	
 	// Measure text widths
 	titleWidth := d.MeasureString(title).Ceil()
 	eastLabelWidth := d.MeasureString(eastLabel).Ceil()
@@ -274,6 +295,11 @@ func (tr *TextRenderer) RenderPassStatus(status *parser.PassStatus, width, heigh
 	if westLabelWidth+westStatusWidth+10 > maxWidth {
 		maxWidth = westLabelWidth + westStatusWidth + 10
 	}
+	for _, line := range closureLines {
+		if lineWidth := d.MeasureString(line).Ceil(); lineWidth > maxWidth {
+			maxWidth = lineWidth
+		}
+	}
 	
 	// Measure text height
 	metrics := face.Metrics()
@@ -288,11 +314,12 @@ func (tr *TextRenderer) RenderPassStatus(status *parser.PassStatus, width, heigh
 	padding := 6 // Tight padding around text
 	contentWidth := maxWidth + padding*2
 	contentHeight := lineHeight*3 + padding*2 + 4 // Title + 2 status lines + spacing
+	contentHeight += len(closureLines) * lineHeight
 	
 	// Add space for conditions if closed
-	if status.IsClosed && status.Conditions != "" {
+	if status.IsClosed && conditions != "" {
 		conditionsWidth := contentWidth - padding*2
-		lines := tr.wordWrap(status.Conditions, conditionsWidth, face)
+		lines := tr.wordWrap(conditions, conditionsWidth, face)
 		contentHeight += len(lines) * lineHeight
 	}
 	
@@ -348,13 +375,21 @@ func (tr *TextRenderer) RenderPassStatus(status *parser.PassStatus, width, heigh
 	westStatusX := westLabelX + westLabelWidth + 8
 	drawText(d, westStatusX, westY, westStatus, statusColor)
 	
+	// Closure duration and reopening countdown lines (centered, red)
+	lastY := westY
+	for _, line := range closureLines {
+		lastY += lineHeight
+		lineX := (contentWidth - d.MeasureString(line).Ceil()) / 2
+		drawText(d, lineX, lastY, line, red)
+	}
+	
 	// Conditions text if closed (word wrapped)
-	if status.IsClosed && status.Conditions != "" {
-		conditionsY := westY + lineHeight + 5
+	if status.IsClosed && conditions != "" {
+		conditionsY := lastY + lineHeight + 5
 		conditionsWidth := contentWidth - padding*2
 		
 		// Word wrap the conditions text
-		lines := tr.wordWrap(status.Conditions, conditionsWidth, face)
+		lines := tr.wordWrap(conditions, conditionsWidth, face)
 		
 		// Draw each line
 		d.Src = image.NewUniform(black)
@@ -388,3 +423,266 @@ func (tr *TextRenderer) RenderPassStatus(status *parser.PassStatus, width, heigh
 	
 	return nil
 }
+
+// closureDetails returns the closure detail lines for a closed pass: duration from
+// pass history (e.g. "Closed for 3h 20m") and reopening countdown (e.g. "Reopens in 1h 25m (3:00 PM)")
+func closureDetails(status *parser.PassStatus, now time.Time) []string {
+	if !status.IsClosed {
+		return nil
+	}
+	
+	var lines []string
+	if !status.ClosedSince.IsZero() {
+		lines = append(lines, "Closed for "+history.FormatDuration(now.Sub(status.ClosedSince)))
+	}
+	if countdown := reopeningCountdown(status.Reopening, now); countdown != "" {
+		lines = append(lines, countdown)
+	}
+	return lines
+}
+
+// reopeningCountdown formats the reopening estimate relative to now
+// e.g. "Reopens in 1h 25m (3:00 PM)", "Reopening overdue (est. 3:00 PM)", "Closed overnight"
+func reopeningCountdown(r *parser.Reopening, now time.Time) string {
+	if r == nil {
+		return ""
+	}
+	
+	if !r.Time.IsZero() {
+		clock := r.Time.In(parser.Pacific).Format("3:04 PM")
+		if remaining := r.Time.Sub(now); remaining > 0 {
+			return fmt.Sprintf("Reopens in %s (%s)", history.FormatDuration(remaining), clock)
+		}
+		return fmt.Sprintf("Reopening overdue (est. %s)", clock)
+	}
+	
+	if r.Overnight {
+		return "Closed overnight"
+	}
+	
+	return ""
+}
+
+// RenderPassStrip creates a compact strip showing all configured passes side by side
+// Each column shows the pass name and a short East/West restriction summary
+func (tr *TextRenderer) RenderPassStrip(passes []parser.PassResult, outputPath string) error {
+	if len(passes) == 0 {
+		return fmt.Errorf("no passes to render")
+	}
+	
+	face := tr.boldFace
+	if face == nil {
+		face = basicfont.Face7x13
+	}
+	d := &font.Drawer{Face: face}
+	
+	// Colors by restriction level
+	red := color.RGBA{200, 0, 0, 255}     // Closed
+	orange := color.RGBA{190, 100, 0, 255} // Restrictions in effect
+	black := color.RGBA{0, 0, 0, 255}      // No restrictions / labels
+	gray := color.RGBA{110, 110, 110, 255} // Unknown
+	
+	type stripLine struct {
+		label string
+		value string
+		col   color.Color
+	}
+	
+	directionLine := func(label, value string) stripLine {
+		summary := parser.SummarizeRestriction(value)
+		col := orange
+		switch {
+		case summary == "Closed":
+			col = red
+		case summary == "No restrictions":
+			col = black
+		case summary == "Unknown":
+			col = gray
+		}
+		return stripLine{label: label, value: summary, col: col}
+	}
+	
+	// Build the two direction lines for each pass
+	columns := make([][]stripLine, len(passes))
+	for i, pr := range passes {
+		if pr.Status == nil {
+			columns[i] = []stripLine{
+				{label: "E", value: "Unknown", col: gray},
+				{label: "W", value: "Unknown", col: gray},
+			}
+			continue
+		}
+		columns[i] = []stripLine{
+			directionLine("E", pr.Status.East),
+			directionLine("W", pr.Status.West),
+		}
+	}
+	
+	// All columns share the widest column's width
+	labelGap := 8
+	labelWidth := d.MeasureString("W").Ceil()
+	colWidth := 0
+	for i, pr := range passes {
+		if w := d.MeasureString(pr.Pass.Name).Ceil(); w > colWidth {
+			colWidth = w
+		}
+		for _, line := range columns[i] {
+			if w := labelWidth + labelGap + d.MeasureString(line.value).Ceil(); w > colWidth {
+				colWidth = w
+			}
+		}
+	}
+	
+	// Tighter line spacing than the pass status graphic to keep the strip compact
+	metrics := face.Metrics()
+	ascent := metrics.Ascent.Ceil()
+	lineHeight := metrics.Height.Ceil() + 6
+	
+	padding := 10
+	cellWidth := colWidth + padding*2
+	width := cellWidth * len(passes)
+	height := padding*2 + ascent + metrics.Descent.Ceil() + lineHeight*2
+	
+	// White background at 50% transparency, matching the pass status graphic
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(img, img.Bounds(), image.NewUniform(color.RGBA{255, 255, 255, 128}), image.Point{}, draw.Src)
+	
+	d = &font.Drawer{Dst: img, Face: face}
+	drawText := func(x, y int, text string, col color.Color) {
+		d.Src = image.NewUniform(col)
+		d.Dot = fixed.Point26_6{X: fixed.I(x), Y: fixed.I(y)}
+		d.DrawString(text)
+	}
+	
+	divider := image.NewUniform(color.RGBA{0, 0, 0, 96})
+	for i, pr := range passes {
+		x := i*cellWidth + padding
+		
+		// Thin divider between columns
+		if i > 0 {
+			draw.Draw(img, image.Rect(i*cellWidth, padding, i*cellWidth+1, height-padding), divider, image.Point{}, draw.Over)
+		}
+		
+		// Pass name (centered)
+		nameX := i*cellWidth + (cellWidth-d.MeasureString(pr.Pass.Name).Ceil())/2
+		y := padding + ascent
+		drawText(nameX, y, pr.Pass.Name, black)
+		
+		for _, line := range columns[i] {
+			y += lineHeight
+			drawText(x, y, line.label, black)
+			drawText(x+labelWidth+labelGap, y, line.value, line.col)
+		}
+	}
+	
+	f, err := os.Create(outputPath)
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+	defer f.Close()
+	
+	if err := png.Encode(f, img); err != nil {
+		return fmt.Errorf("failed to encode image: %w", err)
+	}
+	
+	return nil
+}
+
+// RenderTextPanel renders a titled, word-wrapped text panel of a fixed size
+// Paragraphs are separated by "\n\n"; text that doesn't fit is truncated with "..."
+func (tr *TextRenderer) RenderTextPanel(title, body string, width, height int, outputPath string) error {
+	titleFace, bodyFace := tr.boldFace, tr.bodyFace
+	if titleFace == nil {
+		titleFace = basicfont.Face7x13
+	}
+	if bodyFace == nil {
+		bodyFace = titleFace
+	}
+	
+	padding := 12
+	textWidth := width - padding*2
+	
+	// Mostly opaque white so dense text stays readable over the satellite image
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(img, img.Bounds(), image.NewUniform(color.RGBA{255, 255, 255, 200}), image.Point{}, draw.Src)
+	
+	black := color.RGBA{0, 0, 0, 255}
+	d := &font.Drawer{Dst: img, Src: image.NewUniform(black)}
+	
+	// Title with a rule underneath
+	d.Face = titleFace
+	titleMetrics := titleFace.Metrics()
+	titleY := padding + titleMetrics.Ascent.Ceil()
+	d.Dot = fixed.Point26_6{X: fixed.I(padding), Y: fixed.I(titleY)}
+	d.DrawString(title)
+	
+	ruleY := titleY + titleMetrics.Descent.Ceil() + 4
+	draw.Draw(img, image.Rect(padding, ruleY, width-padding, ruleY+1), image.NewUniform(color.RGBA{0, 0, 0, 128}), image.Point{}, draw.Over)
+	
+	// Body lines that fit in the box
+	bodyMetrics := bodyFace.Metrics()
+	lineHeight := bodyMetrics.Height.Ceil() + 4
+	bodyTop := ruleY + 8
+	maxLines := (height - bodyTop - padding - bodyMetrics.Descent.Ceil()) / lineHeight
+	lines := tr.panelLines(body, textWidth, maxLines, bodyFace)
+	
+	d.Face = bodyFace
+	y := bodyTop + bodyMetrics.Ascent.Ceil()
+	for _, line := range lines {
+		d.Dot = fixed.Point26_6{X: fixed.I(padding), Y: fixed.I(y)}
+		d.DrawString(line)
+		y += lineHeight
+	}
+	
+	f, err := os.Create(outputPath)
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+	defer f.Close()
+	
+	if err := png.Encode(f, img); err != nil {
+		return fmt.Errorf("failed to encode image: %w", err)
+	}
+	
+	return nil
+}
+
+// panelLines word-wraps each paragraph, with an empty line between paragraphs,
+// and truncates the result to maxLines with "..." on the last line
+func (tr *TextRenderer) panelLines(body string, maxWidth, maxLines int, face font.Face) []string {
+	var lines []string
+	for i, paragraph := range strings.Split(body, "\n\n") {
+		if i > 0 {
+			lines = append(lines, "")
+		}
+		lines = append(lines, tr.wordWrap(paragraph, maxWidth, face)...)
+	}
+	
+	if maxLines < 0 {
+		maxLines = 0
+	}
+	if len(lines) > maxLines {
+		lines = lines[:maxLines]
+		for len(lines) > 0 && lines[len(lines)-1] == "" {
+			lines = lines[:len(lines)-1]
+		}
+		if len(lines) > 0 {
+			lines[len(lines)-1] = tr.ellipsize(lines[len(lines)-1], maxWidth, face)
+		}
+	}
+	return lines
+}
+
+// ellipsize appends "..." to a line, dropping words until it fits within maxWidth
+func (tr *TextRenderer) ellipsize(line string, maxWidth int, face font.Face) string {
+	d := &font.Drawer{Face: face}
+	words := strings.Fields(line)
+	for len(words) > 0 {
+		candidate := strings.TrimRight(strings.Join(words, " "), ",;:.") + "..."
+		if d.MeasureString(candidate).Ceil() <= maxWidth {
+			return candidate
+		}
+		words = words[:len(words)-1]
+	}
+	return "..."
+}
@@ -0,0 +1,143 @@
+package image
+
+import (
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/trodemaster/weatherdesktop/pkg/parser"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+)
+
+func TestClosureDetails(t *testing.T) {
+	now := time.Date(2025, 1, 15, 16, 0, 0, 0, parser.Pacific)
+
+	tests := []struct {
+		name   string
+		status parser.PassStatus
+		want   []string
+	}{
+		{"closed with history", parser.PassStatus{IsClosed: true, ClosedSince: now.Add(-200 * time.Minute)}, []string{"Closed for 3h 20m"}},
+		{"closed for days", parser.PassStatus{IsClosed: true, ClosedSince: now.Add(-50 * time.Hour)}, []string{"Closed for 2d 2h"}},
+		{"closed without history", parser.PassStatus{IsClosed: true}, nil},
+		{"closed with reopening", parser.PassStatus{
+			IsClosed: true, ClosedSince: now.Add(-time.Hour),
+			Reopening: &parser.Reopening{Text: "Estimated reopening 5:30 PM.", Time: now.Add(90 * time.Minute)},
+		}, []string{"Closed for 1h 0m", "Reopens in 1h 30m (5:30 PM)"}},
+		{"open", parser.PassStatus{ClosedSince: now.Add(-time.Hour)}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := closureDetails(&tt.status, now); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("closureDetails() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReopeningCountdown(t *testing.T) {
+	now := time.Date(2025, 1, 15, 16, 0, 0, 0, parser.Pacific)
+
+	tests := []struct {
+		name      string
+		reopening *parser.Reopening
+		want      string
+	}{
+		{"future", &parser.Reopening{Time: now.Add(85 * time.Minute)}, "Reopens in 1h 25m (5:25 PM)"},
+		{"under an hour", &parser.Reopening{Time: now.Add(20 * time.Minute)}, "Reopens in 20m (4:20 PM)"},
+		{"utc estimate", &parser.Reopening{Time: now.Add(2 * time.Hour).UTC()}, "Reopens in 2h 0m (6:00 PM)"},
+		{"overdue", &parser.Reopening{Time: now.Add(-30 * time.Minute)}, "Reopening overdue (est. 3:30 PM)"},
+		{"due now", &parser.Reopening{Time: now}, "Reopening overdue (est. 4:00 PM)"},
+		{"overnight", &parser.Reopening{Text: "The pass will remain closed overnight.", Overnight: true}, "Closed overnight"},
+		{"time beats overnight", &parser.Reopening{Time: now.Add(16 * time.Hour), Overnight: true}, "Reopens in 16h 0m (8:00 AM)"},
+		{"text only", &parser.Reopening{Text: "Reopening to be determined."}, ""},
+		{"none", nil, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := reopeningCountdown(tt.reopening, now); got != tt.want {
+				t.Errorf("reopeningCountdown() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// afdShortTerm is a short term AFD section, repeated to overflow a small panel
+const afdShortTerm = `A strong frontal system will bring heavy mountain snow and gusty winds to the Cascades through Saturday, with snow levels falling to 2000 feet behind the front.
+
+Showers taper Sunday as high pressure builds offshore. Pass travel will remain difficult through the weekend.`
+
+func TestPanelLines(t *testing.T) {
+	tr := &TextRenderer{}
+	face := basicfont.Face7x13
+	d := &font.Drawer{Face: face}
+	body := strings.Repeat(afdShortTerm+"\n\n", 4)
+
+	lines := tr.panelLines(body, 200, 6, face)
+	if len(lines) != 6 {
+		t.Fatalf("panelLines() returned %d lines, want 6", len(lines))
+	}
+	if last := lines[len(lines)-1]; !strings.HasSuffix(last, "...") {
+		t.Errorf("last line = %q, want it cut off with an ellipsis", last)
+	}
+	for i, line := range lines {
+		if w := d.MeasureString(line).Ceil(); w > 200 {
+			t.Errorf("line %d %q is %dpx wide, want at most 200", i, line, w)
+		}
+	}
+
+	// Text that fits is left alone
+	lines = tr.panelLines("Dry and mild.", 200, 6, face)
+	if !reflect.DeepEqual(lines, []string{"Dry and mild."}) {
+		t.Errorf("panelLines() = %q, want the text unchanged", lines)
+	}
+
+	// A truncation point on a paragraph break drops the empty line before the ellipsis
+	lines = tr.panelLines("One.\n\nTwo.", 200, 2, face)
+	if !reflect.DeepEqual(lines, []string{"One..."}) {
+		t.Errorf("panelLines() = %q, want [\"One...\"]", lines)
+	}
+}
+
+func TestRenderTextPanel_Overflow(t *testing.T) {
+	tr := &TextRenderer{}
+	path := filepath.Join(t.TempDir(), "afd.png")
+	if err := tr.RenderTextPanel("Forecast Discussion - Short Term", strings.Repeat(afdShortTerm+"\n\n", 10), 300, 160, path); err != nil {
+		t.Fatalf("RenderTextPanel() error = %v", err)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	img, err := png.Decode(f)
+	if err != nil {
+		t.Fatalf("failed to decode panel: %v", err)
+	}
+
+	if b := img.Bounds(); b.Dx() != 300 || b.Dy() != 160 {
+		t.Fatalf("panel is %dx%d, want 300x160", b.Dx(), b.Dy())
+	}
+
+	// Nothing is drawn into the padding: the overflow was truncated, not clipped at the edge
+	background := color.NRGBAModel.Convert(color.RGBA{255, 255, 255, 200})
+	for y := 0; y < 160; y++ {
+		for x := 0; x < 300; x++ {
+			if x >= 12 && x < 288 && y < 148 {
+				continue
+			}
+			if got := color.NRGBAModel.Convert(img.At(x, y)); got != background {
+				t.Fatalf("pixel (%d, %d) = %v in the padding, want background %v", x, y, got, background)
+			}
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package parser
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// AFD section names as they appear in the product headers
+const (
+	SectionSynopsis  = "SYNOPSIS"
+	SectionShortTerm = "SHORT TERM"
+	SectionLongTerm  = "LONG TERM"
+	SectionAviation  = "AVIATION"
+)
+
+// AFDSection is one ".NAME /PERIOD/..." section of an Area Forecast Discussion
+type AFDSection struct {
+	Name   string // e.g. "SHORT TERM"
+	Period string // e.g. "TODAY THROUGH FRIDAY" (empty if the header has none)
+	Text   string // Paragraphs joined with blank lines, hard line wraps removed
+}
+
+// AFD is a parsed NWS Area Forecast Discussion product
+type AFD struct {
+	Office   string    // e.g. "SEW" from the AFDSEW product line
+	IssuedAt time.Time // Issuance time from the header (zero if not found)
+	Sections []AFDSection
+}
+
+var (
+	// afdHeader matches ".SHORT TERM /TODAY THROUGH FRIDAY/...Text" and ".SYNOPSIS...Text"
+	afdHeader = regexp.MustCompile(`^\.([A-Z][A-Z0-9 ]*?)\s*(?:/([^/]*)/)?\s*\.\.\.(.*)$`)
+
+	// afdIssued matches "236 AM PST Wed Jan 10 2024"
+	afdIssued = regexp.MustCompile(`^(\d{1,2})(\d{2}) (AM|PM) ([A-Z]{3}) (\w{3} \w{3} \d{1,2} \d{4})$`)
+)
+
+// Section returns the section with the given name
+func (a *AFD) Section(name string) (AFDSection, bool) {
+	for _, s := range a.Sections {
+		if s.Name == name {
+			return s, true
+		}
+	}
+	return AFDSection{}, false
+}
+
+// ParseAFDFile parses an Area Forecast Discussion saved as plain text
+func (p *Parser) ParseAFDFile(path string) (*AFD, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open AFD file: %w", err)
+	}
+	return p.ParseAFD(string(data))
+}
+
+// ParseAFD splits an Area Forecast Discussion into its sections
+// Sections run from their ".NAME..." header to the next "&&", "$$" or header
+func (p *Parser) ParseAFD(text string) (*AFD, error) {
+	afd := &AFD{}
+
+	var current *AFDSection
+	var paragraphs []string
+	var paragraph []string
+
+	flushParagraph := func() {
+		if len(paragraph) > 0 {
+			paragraphs = append(paragraphs, strings.Join(paragraph, " "))
+			paragraph = nil
+		}
+	}
+	flushSection := func() {
+		flushParagraph()
+		if current != nil {
+			current.Text = strings.Join(paragraphs, "\n\n")
+			afd.Sections = append(afd.Sections, *current)
+		}
+		current = nil
+		paragraphs = nil
+	}
+
+	for _, line := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		line = strings.TrimSpace(line)
+
+		switch {
+		case strings.HasPrefix(line, "AFD") && afd.Office == "" && len(line) == 6:
+			afd.Office = strings.TrimPrefix(line, "AFD")
+			continue
+		case afd.IssuedAt.IsZero() && afdIssued.MatchString(line):
+			afd.IssuedAt = parseAFDIssued(line)
+			continue
+		case line == "&&" || line == "$$":
+			flushSection()
+			continue
+		}
+
+		if m := afdHeader.FindStringSubmatch(line); m != nil {
+			flushSection()
+			current = &AFDSection{
+				Name:   strings.TrimSpace(m[1]),
+				Period: strings.TrimSpace(m[2]),
+			}
+			if rest := strings.TrimSpace(m[3]); rest != "" {
+				paragraph = append(paragraph, rest)
+			}
+			continue
+		}
+
+		if current == nil {
+			continue
+		}
+
+		if line == "" {
+			flushParagraph()
+		} else {
+			paragraph = append(paragraph, line)
+		}
+	}
+	flushSection()
+
+	if len(afd.Sections) == 0 {
+		return nil, fmt.Errorf("no AFD sections found")
+	}
+
+	return afd, nil
+}
+
+// parseAFDIssued parses the AFD issuance line in Pacific time
+func parseAFDIssued(line string) time.Time {
+	m := afdIssued.FindStringSubmatch(line)
+	if m == nil {
+		return time.Time{}
+	}
+
+	// "236 AM PST ..." -> "2:36 AM PST ..." so time.Parse can read the hour
+	value := fmt.Sprintf("%s:%s %s %s %s", m[1], m[2], m[3], m[4], m[5])
+	t, err := time.ParseInLocation("3:04 PM MST Mon Jan 2 2006", value, Pacific)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
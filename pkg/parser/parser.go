@@ -1,20 +1,59 @@
 package parser
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"regexp"
 	"strings"
+	"time"
 
+	"github.com/trodemaster/weatherdesktop/pkg/assets"
 	"golang.org/x/net/html"
 )
 
 // PassStatus represents the status of a mountain pass
 type PassStatus struct {
+	Name       string // Pass name from the pass definition (empty for single-file parses)
 	East       string
 	West       string
 	IsClosed   bool
 	Conditions string
+
+	// Reopening is the estimated reopening parsed from Conditions (nil if none)
+	Reopening *Reopening
+
+	// LastUpdated is the page's "Last updated" time in Pacific time (zero if missing)
+	LastUpdated time.Time
+
+	// ClosedSince is the start of the current closure, filled in from pass
+	// history by the caller (zero when open or unknown)
+	ClosedSince time.Time
+}
+
+// ErrNoPassData is matched by errors.Is when a page has no pass status labels
+var ErrNoPassData = errors.New("no pass data found")
+
+// NoPassDataError is returned when the WSDOT page does not contain the
+// expected "Travel eastbound"/"Travel westbound" condition labels.
+// This usually means WSDOT changed the page layout (schema drift) and the
+// pass status cannot be trusted.
+type NoPassDataError struct {
+	Path   string   // HTML file that was parsed
+	Labels []string // condition labels that were found instead
+}
+
+// Error implements the error interface
+func (e *NoPassDataError) Error() string {
+	if len(e.Labels) == 0 {
+		return fmt.Sprintf("%v in %s: no condition labels matched", ErrNoPassData, e.Path)
+	}
+	return fmt.Sprintf("%v in %s: travel labels missing (found: %s)", ErrNoPassData, e.Path, strings.Join(e.Labels, ", "))
+}
+
+// Is allows errors.Is(err, ErrNoPassData)
+func (e *NoPassDataError) Is(target error) bool {
+	return target == ErrNoPassData
 }
 
 // Parser handles HTML parsing
@@ -55,6 +94,10 @@ func (p *Parser) ParseWSDOTPassStatus(htmlPath string) (*PassStatus, error) {
 	// 	fmt.Printf("DEBUG: Condition %d - Label: %q, Value: %q\n", i, cond.label, cond.value)
 	// }
 	
+	// Track which travel labels were present so layout changes are detected
+	// instead of silently reporting the default "Open" status
+	var foundEast, foundWest bool
+
 	// Look for "Travel eastbound" and "Travel westbound" labels
 	for _, cond := range conditions {
 		label := strings.ToLower(cond.label)
@@ -64,12 +107,14 @@ func (p *Parser) ParseWSDOTPassStatus(htmlPath string) (*PassStatus, error) {
 		// fmt.Printf("DEBUG: Found condition - Label: %q, Value: %q\n", cond.label, value)
 		
 		if strings.Contains(label, "travel") && strings.Contains(label, "eastbound") {
+			foundEast = true
 			status.East = value
 			if strings.Contains(value, "Closed") || strings.Contains(value, "closed") {
 				status.IsClosed = true
 			}
 		}
 		if strings.Contains(label, "travel") && strings.Contains(label, "westbound") {
+			foundWest = true
 			status.West = value
 			if strings.Contains(value, "Closed") || strings.Contains(value, "closed") {
 				status.IsClosed = true
@@ -78,12 +123,96 @@ func (p *Parser) ParseWSDOTPassStatus(htmlPath string) (*PassStatus, error) {
 		if strings.Contains(label, "conditions") && status.IsClosed {
 			status.Conditions = p.cleanConditionsText(value)
 		}
+		if strings.Contains(label, "last updated") {
+			if t, ok := parseLastUpdated(p.cleanConditionsText(value)); ok {
+				status.LastUpdated = t
+			}
+		}
 	}
-	
-	
+
+	// Extract the reopening estimate, resolving clock times against the page update time
+	if status.Conditions != "" {
+		ref := status.LastUpdated
+		if ref.IsZero() {
+			ref = time.Now()
+		}
+		status.Reopening = parseReopening(status.Conditions, ref)
+	}
+
+	if !foundEast || !foundWest {
+		var labels []string
+		for _, cond := range conditions {
+			if cond.label != "" {
+				labels = append(labels, cond.label)
+			}
+		}
+		return nil, &NoPassDataError{Path: htmlPath, Labels: labels}
+	}
+
 	return status, nil
 }
 
+// PassResult is the parse result for one configured pass
+type PassResult struct {
+	Pass   assets.PassDefinition
+	Status *PassStatus // nil if Err is set
+	Err    error
+}
+
+// ParseWSDOTPasses parses the HTML file of each pass definition
+// Results are returned in definition order so callers can lay them out consistently
+func (p *Parser) ParseWSDOTPasses(passes []assets.PassDefinition) []PassResult {
+	results := make([]PassResult, 0, len(passes))
+	for _, pass := range passes {
+		status, err := p.ParseWSDOTPassStatus(pass.HTMLPath)
+		if status != nil {
+			status.Name = pass.Name
+		}
+		results = append(results, PassResult{Pass: pass, Status: status, Err: err})
+	}
+	return results
+}
+
+// IsDirectionClosed reports whether a direction status indicates a closure
+// (contains "closed" and not "no restrictions")
+func IsDirectionClosed(value string) bool {
+	v := strings.ToLower(value)
+	return strings.Contains(v, "closed") && !strings.Contains(v, "no restrictions")
+}
+
+// SummarizeRestriction shortens a direction status for compact display
+// e.g. "Traction Tires Required, Chains required on Vehicles over 10,000 ..." -> "Traction tires"
+func SummarizeRestriction(value string) string {
+	v := strings.ToLower(strings.TrimSpace(value))
+
+	switch {
+	case v == "":
+		return "Unknown"
+	case IsDirectionClosed(v):
+		return "Closed"
+	case strings.Contains(v, "no restrictions"):
+		return "No restrictions"
+	case strings.Contains(v, "chains required on all vehicles"):
+		return "Chains required"
+	case strings.Contains(v, "chains required") && !strings.Contains(v, "vehicles over"):
+		return "Chains required"
+	case strings.Contains(v, "traction tires"):
+		return "Traction tires"
+	case strings.Contains(v, "oversize"):
+		return "Oversize prohibited"
+	}
+
+	// Fall back to the first clause, truncated
+	summary := strings.TrimSpace(value)
+	if i := strings.IndexAny(summary, ",."); i > 0 {
+		summary = summary[:i]
+	}
+	if len(summary) > 24 {
+		summary = strings.TrimSpace(summary[:23]) + "…"
+	}
+	return summary
+}
+
 // conditionPair represents a label-value pair
 type conditionPair struct {
 	label string
@@ -1,9 +1,14 @@
 package parser
 
 import (
+	"errors"
 	"path/filepath"
 	"runtime"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/trodemaster/weatherdesktop/pkg/assets"
 )
 
 func getTestFilePath(filename string) string {
@@ -144,3 +149,223 @@ func TestParseWSDOTPassStatus_ClosedWest(t *testing.T) {
 	t.Logf("  IsClosed: %v", status.IsClosed)
 }
 
+
+func TestParseWSDOTPassStatus_Malformed(t *testing.T) {
+	p := New()
+	path := getTestFilePath("malformed_wsdot_stevens_pass.html")
+	status, err := p.ParseWSDOTPassStatus(path)
+	if err == nil {
+		t.Fatalf("Expected no pass data error, got status %+v", status)
+	}
+
+	if !errors.Is(err, ErrNoPassData) {
+		t.Errorf("Expected errors.Is(err, ErrNoPassData), got %v", err)
+	}
+
+	var noData *NoPassDataError
+	if !errors.As(err, &noData) {
+		t.Fatalf("Expected *NoPassDataError, got %T", err)
+	}
+
+	if noData.Path != path {
+		t.Errorf("Expected Path=%q, got %q", path, noData.Path)
+	}
+
+	if len(noData.Labels) != 1 || noData.Labels[0] != "Last updated" {
+		t.Errorf("Expected Labels=[Last updated], got %v", noData.Labels)
+	}
+
+	t.Logf("✓ Schema drift detected correctly")
+	t.Logf("  Error: %v", err)
+}
+
+func TestParseWSDOTPassStatus_Restrictions(t *testing.T) {
+	p := New()
+	status, err := p.ParseWSDOTPassStatus(getTestFilePath("wsdot_stevens_pass.html"))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if status.IsClosed {
+		t.Error("Expected IsClosed=false for pass with restrictions")
+	}
+
+	if !strings.Contains(status.East, "Traction Tires Required") {
+		t.Errorf("Expected East to contain restrictions, got '%s'", status.East)
+	}
+
+	t.Logf("✓ Restrictions parsed correctly")
+	t.Logf("  East: %s", status.East)
+	t.Logf("  West: %s", status.West)
+}
+
+func TestParseWSDOTPassStatus_Reopening(t *testing.T) {
+	tests := []struct {
+		file      string
+		text      string
+		time      time.Time
+		overnight bool
+	}{
+		{
+			file: "closed_reopening_wsdot_stevens_pass.html",
+			text: "Estimated reopening 3:00 PM.",
+			time: time.Date(2024, 1, 9, 15, 0, 0, 0, Pacific),
+		},
+		{
+			file:      "closed_overnight_wsdot_stevens_pass.html",
+			text:      "The highway will remain closed overnight.",
+			overnight: true,
+		},
+		{
+			file: "closed_wsdot_stevens_pass.html",
+			text: "There is currently no estimated time for reopening and there is no detour available.",
+		},
+	}
+
+	p := New()
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			status, err := p.ParseWSDOTPassStatus(getTestFilePath(tt.file))
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			if status.Reopening == nil {
+				t.Fatalf("Expected reopening estimate, got nil (conditions: %s)", status.Conditions)
+			}
+
+			if status.Reopening.Text != tt.text {
+				t.Errorf("Expected Text=%q, got %q", tt.text, status.Reopening.Text)
+			}
+
+			if !status.Reopening.Time.Equal(tt.time) {
+				t.Errorf("Expected Time=%v, got %v", tt.time, status.Reopening.Time)
+			}
+
+			if status.Reopening.Overnight != tt.overnight {
+				t.Errorf("Expected Overnight=%v, got %v", tt.overnight, status.Reopening.Overnight)
+			}
+
+			t.Logf("✓ Reopening parsed: %q (time: %v, overnight: %v)", status.Reopening.Text, status.Reopening.Time, status.Reopening.Overnight)
+		})
+	}
+}
+
+func TestParseReopening_Phrasings(t *testing.T) {
+	ref := time.Date(2024, 1, 9, 17, 29, 0, 0, Pacific)
+
+	tests := []struct {
+		conditions string
+		time       time.Time
+	}{
+		{"Estimated reopening 7:00 PM.", time.Date(2024, 1, 9, 19, 0, 0, 0, Pacific)},
+		{"The pass is expected to reopen at 9 p.m. tonight.", time.Date(2024, 1, 9, 21, 0, 0, 0, Pacific)},
+		{"Crews estimate reopening at 6 a.m. tomorrow.", time.Date(2024, 1, 10, 6, 0, 0, 0, Pacific)},
+		{"Estimated reopening time is 11:30am.", time.Date(2024, 1, 10, 11, 30, 0, 0, Pacific)},
+		{"The highway is estimated to reopen by noon tomorrow.", time.Date(2024, 1, 10, 12, 0, 0, 0, Pacific)},
+	}
+
+	for _, tt := range tests {
+		r := parseReopening("US 2 is closed at milepost 58.5 due to a collision. "+tt.conditions, ref)
+		if r == nil {
+			t.Errorf("Expected reopening for %q, got nil", tt.conditions)
+			continue
+		}
+		if r.Text != tt.conditions {
+			t.Errorf("Expected Text=%q, got %q", tt.conditions, r.Text)
+		}
+		if !r.Time.Equal(tt.time) {
+			t.Errorf("%q: expected %v, got %v", tt.conditions, tt.time, r.Time)
+		}
+	}
+
+	if r := parseReopening("US 2 is closed due to heavy snow.", ref); r != nil {
+		t.Errorf("Expected no reopening, got %+v", r)
+	}
+}
+
+func TestParseWSDOTPasses(t *testing.T) {
+	passes := []assets.PassDefinition{
+		{Name: "Stevens Pass", Slug: "stevens", HTMLPath: getTestFilePath("wsdot_stevens_pass.html")},
+		{Name: "Snoqualmie Pass", Slug: "snoqualmie", HTMLPath: getTestFilePath("closed_wsdot_stevens_pass.html")},
+		{Name: "Blewett Pass", Slug: "blewett", HTMLPath: getTestFilePath("malformed_wsdot_stevens_pass.html")},
+	}
+
+	p := New()
+	results := p.ParseWSDOTPasses(passes)
+	if len(results) != len(passes) {
+		t.Fatalf("Expected %d results, got %d", len(passes), len(results))
+	}
+
+	want := []struct {
+		name    string
+		summary string
+		err     bool
+	}{
+		{"Stevens Pass", "Traction tires", false},
+		{"Snoqualmie Pass", "Closed", false},
+		{"Blewett Pass", "", true},
+	}
+
+	for i, w := range want {
+		r := results[i]
+		if r.Pass.Name != w.name {
+			t.Errorf("Result %d: expected pass %q, got %q", i, w.name, r.Pass.Name)
+		}
+		if w.err {
+			if !errors.Is(r.Err, ErrNoPassData) || r.Status != nil {
+				t.Errorf("%s: expected ErrNoPassData and nil status, got %v / %+v", w.name, r.Err, r.Status)
+			}
+			continue
+		}
+		if r.Err != nil {
+			t.Fatalf("%s: expected no error, got %v", w.name, r.Err)
+		}
+		if r.Status.Name != w.name {
+			t.Errorf("%s: expected Status.Name=%q, got %q", w.name, w.name, r.Status.Name)
+		}
+		if got := SummarizeRestriction(r.Status.East); got != w.summary {
+			t.Errorf("%s: expected East summary %q, got %q", w.name, w.summary, got)
+		}
+	}
+}
+
+func TestParseAFDFile(t *testing.T) {
+	p := New()
+	afd, err := p.ParseAFDFile(getTestFilePath("afd_sew.txt"))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if afd.Office != "SEW" {
+		t.Errorf("Expected Office='SEW', got '%s'", afd.Office)
+	}
+
+	issued := time.Date(2024, 1, 10, 2, 36, 0, 0, Pacific)
+	if !afd.IssuedAt.Equal(issued) {
+		t.Errorf("Expected IssuedAt=%v, got %v", issued, afd.IssuedAt)
+	}
+
+	for _, name := range []string{SectionSynopsis, SectionShortTerm, SectionLongTerm, SectionAviation} {
+		if _, ok := afd.Section(name); !ok {
+			t.Errorf("Expected section %q", name)
+		}
+	}
+
+	short, _ := afd.Section(SectionShortTerm)
+	if short.Period != "TODAY THROUGH FRIDAY" {
+		t.Errorf("Expected Period='TODAY THROUGH FRIDAY', got '%s'", short.Period)
+	}
+	if !strings.HasPrefix(short.Text, "Satellite imagery early this morning shows") {
+		t.Errorf("Unexpected short term text start: %q", short.Text[:min(60, len(short.Text))])
+	}
+	if paragraphs := strings.Split(short.Text, "\n\n"); len(paragraphs) != 2 {
+		t.Errorf("Expected 2 short term paragraphs, got %d", len(paragraphs))
+	}
+	if strings.Contains(short.Text, "&&") || strings.Contains(short.Text, "LONG TERM") {
+		t.Errorf("Short term text ran into the next section: %q", short.Text)
+	}
+
+	t.Logf("✓ AFD parsed: %d sections", len(afd.Sections))
+	t.Logf("  Short term: %s", short.Text)
+}
@@ -0,0 +1,129 @@
+package parser
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	_ "time/tzdata" // Pacific time zone data (container may not have tzdata installed)
+)
+
+// Reopening is an estimated reopening extracted from the WSDOT conditions text
+type Reopening struct {
+	Text      string    // Original sentence, e.g. "Estimated reopening 3:00 PM."
+	Time      time.Time // Estimated reopening time in Pacific time (zero if no time given)
+	Overnight bool      // Pass will remain closed overnight
+}
+
+// Pacific is the time zone WSDOT reports times in
+var Pacific = mustLoadLocation("America/Los_Angeles")
+
+// lastUpdatedLayout matches WSDOT's "Last updated" value, e.g. "Tuesday, January 9, 2024 5:29 PM"
+const lastUpdatedLayout = "Monday, January 2, 2006 3:04 PM"
+
+var (
+	// sentenceEnd splits on ., ! or ? followed by whitespace and a capital letter
+	// so "58.5" and "3 p.m. tomorrow" stay in one sentence
+	sentenceEnd = regexp.MustCompile(`[.!?]\s+[A-Z]`)
+
+	// clockTime matches "3:00 PM", "3 pm", "3 p.m.", "11:30am"
+	clockTime = regexp.MustCompile(`(?i)\b(\d{1,2})(?::(\d{2}))?\s*([ap])\.?\s?m\b\.?`)
+
+	// noon matches "noon" as a reopening time
+	noon = regexp.MustCompile(`(?i)\bnoon\b`)
+)
+
+func mustLoadLocation(name string) *time.Location {
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		panic(err)
+	}
+	return loc
+}
+
+// parseLastUpdated parses the "Last updated" value in Pacific time
+func parseLastUpdated(value string) (time.Time, bool) {
+	value = strings.TrimSpace(strings.Replace(value, "[Disclaimer]", "", 1))
+	t, err := time.ParseInLocation(lastUpdatedLayout, value, Pacific)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t, true
+}
+
+// parseReopening finds the reopening sentence in the conditions text
+// Clock times are resolved relative to ref (the page's "Last updated" time):
+// a time earlier than ref is assumed to be the next day, as is "tomorrow".
+func parseReopening(conditions string, ref time.Time) *Reopening {
+	for _, sentence := range splitSentences(conditions) {
+		lower := strings.ToLower(sentence)
+		if !strings.Contains(lower, "reopen") && !strings.Contains(lower, "overnight") {
+			continue
+		}
+
+		r := &Reopening{
+			Text:      sentence,
+			Overnight: strings.Contains(lower, "overnight"),
+		}
+
+		// "no estimated time for reopening" - keep the text only
+		if strings.Contains(lower, "no estimated time") {
+			return r
+		}
+
+		hour, minute, ok := parseClock(sentence)
+		if !ok {
+			return r
+		}
+
+		ref = ref.In(Pacific)
+		t := time.Date(ref.Year(), ref.Month(), ref.Day(), hour, minute, 0, 0, Pacific)
+		if strings.Contains(lower, "tomorrow") || t.Before(ref) {
+			t = t.AddDate(0, 0, 1)
+		}
+		r.Time = t
+
+		return r
+	}
+
+	return nil
+}
+
+// parseClock extracts a 24-hour time from text
+func parseClock(text string) (hour, minute int, ok bool) {
+	if m := clockTime.FindStringSubmatch(text); m != nil {
+		hour, _ = strconv.Atoi(m[1])
+		if m[2] != "" {
+			minute, _ = strconv.Atoi(m[2])
+		}
+		if hour < 1 || hour > 12 || minute > 59 {
+			return 0, 0, false
+		}
+		hour %= 12
+		if strings.EqualFold(m[3], "p") {
+			hour += 12
+		}
+		return hour, minute, true
+	}
+
+	if noon.MatchString(text) {
+		return 12, 0, true
+	}
+
+	return 0, 0, false
+}
+
+// splitSentences splits text into trimmed sentences
+func splitSentences(text string) []string {
+	var sentences []string
+	start := 0
+	for _, loc := range sentenceEnd.FindAllStringIndex(text, -1) {
+		// loc ends at the capital letter that starts the next sentence
+		sentences = append(sentences, strings.TrimSpace(text[start:loc[0]+1]))
+		start = loc[1] - 1
+	}
+	if rest := strings.TrimSpace(text[start:]); rest != "" {
+		sentences = append(sentences, rest)
+	}
+	return sentences
+}
@@ -0,0 +1,116 @@
+package playwright
+
+import (
+	"fmt"
+
+	"github.com/playwright-community/playwright-go"
+	"github.com/trodemaster/weatherdesktop/pkg/assets"
+)
+
+// removeElements removes every element matching sel and returns how many were removed
+const removeElements = `(sel) => {
+	const els = document.querySelectorAll(sel);
+	els.forEach((el) => el.remove());
+	return els.length;
+}`
+
+// contextOptions returns the browser context options for a target
+func contextOptions(target assets.ScrapeTarget) (playwright.BrowserNewContextOptions, error) {
+	var opts playwright.BrowserNewContextOptions
+
+	if target.ViewportWidth > 0 && target.ViewportHeight > 0 {
+		opts.Viewport = &playwright.Size{
+			Width:  target.ViewportWidth,
+			Height: target.ViewportHeight,
+		}
+	}
+
+	if target.DeviceScaleFactor > 0 {
+		opts.DeviceScaleFactor = playwright.Float(target.DeviceScaleFactor)
+	}
+
+	switch target.ColorScheme {
+	case "":
+	case "light":
+		opts.ColorScheme = playwright.ColorSchemeLight
+	case "dark":
+		opts.ColorScheme = playwright.ColorSchemeDark
+	case "no-preference":
+		opts.ColorScheme = playwright.ColorSchemeNoPreference
+	default:
+		return opts, fmt.Errorf("unknown color scheme %q", target.ColorScheme)
+	}
+
+	return opts, nil
+}
+
+// runActions applies the target's pre-capture actions in order
+// A failed action is logged and skipped; the capture is still attempted
+func (s *Scraper) runActions(page playwright.Page, target assets.ScrapeTarget) {
+	for _, action := range target.Actions {
+		if err := s.runAction(page, target, action); err != nil {
+			s.logger.Printf("Warning: %s: %s %s failed: %v", target.Name, action.Kind, action.Selector, err)
+		}
+	}
+}
+
+// runAction performs a single pre-capture action
+func (s *Scraper) runAction(page playwright.Page, target assets.ScrapeTarget, action assets.PageAction) error {
+	switch action.Kind {
+	case assets.ActionClickIfPresent:
+		locator := page.Locator(action.Selector).First()
+		count, err := locator.Count()
+		if err != nil {
+			return err
+		}
+		if count == 0 {
+			if s.debug {
+				s.logger.Printf("   Nothing to click: %s", action.Selector)
+			}
+			return nil
+		}
+		if err := locator.Click(playwright.LocatorClickOptions{
+			Timeout: playwright.Float(2000),
+		}); err != nil {
+			return err
+		}
+		if s.debug {
+			s.logger.Printf("🖱️  Clicked: %s", action.Selector)
+		}
+
+	case assets.ActionRemove:
+		removed, err := page.Evaluate(removeElements, action.Selector)
+		if err != nil {
+			return err
+		}
+		if s.debug {
+			s.logger.Printf("🧹 Removed %v element(s): %s", removed, action.Selector)
+		}
+
+	case assets.ActionInjectCSS:
+		if _, err := page.AddStyleTag(playwright.PageAddStyleTagOptions{
+			Content: playwright.String(action.CSS),
+		}); err != nil {
+			return err
+		}
+		if s.debug {
+			s.logger.Printf("🎨 Injected %d bytes of CSS", len(action.CSS))
+		}
+
+	case assets.ActionScrollIntoView:
+		selector := action.Selector
+		if selector == "" {
+			selector = target.Selector
+		}
+		if err := page.Locator(selector).First().ScrollIntoViewIfNeeded(playwright.LocatorScrollIntoViewIfNeededOptions{
+			Timeout: playwright.Float(5000),
+		}); err != nil {
+			return err
+		}
+
+	default:
+		return fmt.Errorf("unknown action kind %q", action.Kind)
+	}
+
+	return nil
+}
@@ -0,0 +1,101 @@
+package playwright
+
+import (
+	"testing"
+
+	"github.com/playwright-community/playwright-go"
+	"github.com/trodemaster/weatherdesktop/pkg/assets"
+)
+
+// actionPage has a consent banner, ads, a sticky header and a chart below the fold
+const actionPage = `<!DOCTYPE html>
+<html><body style="margin: 0">
+<header id="sticky" style="position: sticky; top: 0">Header</header>
+<div id="consent">Cookies? <button id="accept" onclick="document.getElementById('consent').remove()">Accept</button></div>
+<div class="ad">ad 1</div>
+<div class="ad">ad 2</div>
+<div style="height: 3000px"></div>
+<div id="chart" style="height: 200px">chart</div>
+</body></html>`
+
+func TestContextOptions(t *testing.T) {
+	opts, err := contextOptions(assets.ScrapeTarget{ViewportWidth: 1280, ViewportHeight: 720, DeviceScaleFactor: 2, ColorScheme: "dark"})
+	if err != nil {
+		t.Fatalf("contextOptions() error = %v", err)
+	}
+	if opts.Viewport == nil || opts.Viewport.Width != 1280 || opts.Viewport.Height != 720 {
+		t.Errorf("Viewport = %+v, want 1280x720", opts.Viewport)
+	}
+	if opts.DeviceScaleFactor == nil || *opts.DeviceScaleFactor != 2 {
+		t.Errorf("DeviceScaleFactor = %v, want 2", opts.DeviceScaleFactor)
+	}
+	if opts.ColorScheme != playwright.ColorSchemeDark {
+		t.Errorf("ColorScheme = %v, want dark", opts.ColorScheme)
+	}
+
+	if _, err := contextOptions(assets.ScrapeTarget{ColorScheme: "sepia"}); err == nil {
+		t.Error("contextOptions() accepted an unknown color scheme")
+	}
+}
+
+func TestRunAction(t *testing.T) {
+	s, page := newTestPage(t, actionPage)
+	target := assets.ScrapeTarget{Name: "Test", Selector: "#chart"}
+
+	// count returns the number of elements matching sel
+	count := func(sel string) int {
+		n, err := page.Locator(sel).Count()
+		if err != nil {
+			t.Fatalf("failed to count %s: %v", sel, err)
+		}
+		return n
+	}
+
+	tests := []struct {
+		name    string
+		action  assets.PageAction
+		wantErr bool
+		check   func(t *testing.T)
+	}{
+		{"click missing", assets.PageAction{Kind: assets.ActionClickIfPresent, Selector: "#no-such-button"}, false, nil},
+		{"click present", assets.PageAction{Kind: assets.ActionClickIfPresent, Selector: "#accept"}, false, func(t *testing.T) {
+			if n := count("#consent"); n != 0 {
+				t.Errorf("consent banner still present after click (%d)", n)
+			}
+		}},
+		{"remove", assets.PageAction{Kind: assets.ActionRemove, Selector: ".ad"}, false, func(t *testing.T) {
+			if n := count(".ad"); n != 0 {
+				t.Errorf("%d ad(s) left after remove", n)
+			}
+		}},
+		{"inject css", assets.PageAction{Kind: assets.ActionInjectCSS, CSS: "#sticky { display: none !important; }"}, false, func(t *testing.T) {
+			visible, err := page.Locator("#sticky").IsVisible()
+			if err != nil || visible {
+				t.Errorf("sticky header visible = %v (err %v) after inject_css, want hidden", visible, err)
+			}
+		}},
+		{"scroll into view", assets.PageAction{Kind: assets.ActionScrollIntoView}, false, func(t *testing.T) {
+			scrolled, err := page.Evaluate("() => window.scrollY > 0")
+			if err != nil {
+				t.Fatalf("failed to read scroll position: %v", err)
+			}
+			if scrolled != true {
+				t.Error("page not scrolled to the target")
+			}
+		}},
+		{"scroll missing", assets.PageAction{Kind: assets.ActionScrollIntoView, Selector: "#no-such-chart"}, true, nil},
+		{"unknown kind", assets.PageAction{Kind: "bogus"}, true, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := s.runAction(page, target, tt.action)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("runAction() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.check != nil {
+				tt.check(t)
+			}
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package playwright
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/png"
+	"math"
+
+	"github.com/trodemaster/weatherdesktop/pkg/assets"
+)
+
+// Capture content thresholds
+const (
+	maxDominantShare = 0.97 // Share of sampled pixels in the most common color
+	minLumaStdDev    = 3.0  // Luminance standard deviation (0-255 scale)
+	maxSizeRatio     = 2.0  // Allowed factor between actual and expected size per dimension
+	maxCheckSamples  = 100000
+)
+
+// ErrBlankCapture is returned for screenshots that look blank, half-rendered or of the wrong element
+var ErrBlankCapture = errors.New("capture looks blank")
+
+// checkCapture inspects a PNG screenshot for blank or placeholder content
+// expected is the expected size in device pixels (zero = skip the size check)
+func checkCapture(data []byte, expected image.Point) error {
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to decode screenshot: %w", err)
+	}
+
+	size := img.Bounds().Size()
+	if size.X == 0 || size.Y == 0 {
+		return fmt.Errorf("%w: empty %dx%d image", ErrBlankCapture, size.X, size.Y)
+	}
+
+	if expected.X > 0 && expected.Y > 0 {
+		if !withinRatio(size.X, expected.X) || !withinRatio(size.Y, expected.Y) {
+			return fmt.Errorf("%w: size %dx%d, expected about %dx%d", ErrBlankCapture, size.X, size.Y, expected.X, expected.Y)
+		}
+	}
+
+	share, stddev := captureStats(img)
+	if share > maxDominantShare {
+		return fmt.Errorf("%w: %.1f%% of pixels are one color", ErrBlankCapture, share*100)
+	}
+	if stddev < minLumaStdDev {
+		return fmt.Errorf("%w: luminance std dev %.2f", ErrBlankCapture, stddev)
+	}
+
+	return nil
+}
+
+// expectedPixels returns the target's expected screenshot size in device pixels
+func expectedPixels(target assets.ScrapeTarget) image.Point {
+	if target.DeviceScaleFactor <= 0 {
+		return target.ExpectedSize
+	}
+	return image.Point{
+		X: int(float64(target.ExpectedSize.X) * target.DeviceScaleFactor),
+		Y: int(float64(target.ExpectedSize.Y) * target.DeviceScaleFactor),
+	}
+}
+
+// captureStats samples the image on a grid and returns the share of the most common
+// color (quantized to 4 bits per channel) and the luminance standard deviation
+func captureStats(img image.Image) (dominantShare, lumaStdDev float64) {
+	b := img.Bounds()
+
+	// Sample at most ~maxCheckSamples pixels, evenly spread
+	step := 1
+	for (b.Dx()/step)*(b.Dy()/step) > maxCheckSamples {
+		step++
+	}
+
+	counts := make(map[uint32]int)
+	var n, sum, sumSq float64
+	for y := b.Min.Y; y < b.Max.Y; y += step {
+		for x := b.Min.X; x < b.Max.X; x += step {
+			r, g, bl, _ := img.At(x, y).RGBA()
+			r8, g8, b8 := r>>8, g>>8, bl>>8
+
+			counts[(r8>>4)<<8|(g8>>4)<<4|b8>>4]++
+
+			luma := 0.299*float64(r8) + 0.587*float64(g8) + 0.114*float64(b8)
+			sum += luma
+			sumSq += luma * luma
+			n++
+		}
+	}
+
+	var top int
+	for _, c := range counts {
+		if c > top {
+			top = c
+		}
+	}
+
+	mean := sum / n
+	variance := math.Max(sumSq/n-mean*mean, 0)
+	return float64(top) / n, math.Sqrt(variance)
+}
+
+// withinRatio reports whether actual is within maxSizeRatio of expected
+func withinRatio(actual, expected int) bool {
+	ratio := float64(actual) / float64(expected)
+	return ratio >= 1/maxSizeRatio && ratio <= maxSizeRatio
+}
@@ -0,0 +1,78 @@
+package playwright
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+)
+
+// encodePNG renders a w x h image with fill and returns it as PNG bytes
+func encodePNG(t *testing.T, w, h int, fill func(x, y int) color.Color) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, fill(x, y))
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatalf("failed to encode test image: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestCheckCapture(t *testing.T) {
+	white := func(x, y int) color.Color { return color.White }
+
+	// White page with a small dark spinner in the middle
+	spinner := func(x, y int) color.Color {
+		if x >= 190 && x < 210 && y >= 140 && y < 160 {
+			return color.Black
+		}
+		return color.White
+	}
+
+	// Chart-like content: white background with a grid and a filled area
+	chart := func(x, y int) color.Color {
+		switch {
+		case x%40 == 0 || y%40 == 0:
+			return color.RGBA{200, 200, 200, 255}
+		case y > 200-x/4:
+			return color.RGBA{70, 130, 180, 255}
+		}
+		return color.White
+	}
+
+	tests := []struct {
+		name     string
+		data     []byte
+		expected image.Point
+		blank    bool
+	}{
+		{"uniform white", encodePNG(t, 400, 300, white), image.Point{}, true},
+		{"loading spinner", encodePNG(t, 400, 300, spinner), image.Point{}, true},
+		{"chart", encodePNG(t, 400, 300, chart), image.Point{}, false},
+		{"chart at expected size", encodePNG(t, 400, 300, chart), image.Point{X: 380, Y: 320}, false},
+		{"wrong element", encodePNG(t, 400, 30, chart), image.Point{X: 400, Y: 300}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkCapture(tt.data, tt.expected)
+			if got := errors.Is(err, ErrBlankCapture); got != tt.blank {
+				t.Errorf("checkCapture() error = %v, want blank %v", err, tt.blank)
+			}
+		})
+	}
+}
+
+func TestCheckCapture_InvalidPNG(t *testing.T) {
+	err := checkCapture([]byte("not a png"), image.Point{})
+	if err == nil || errors.Is(err, ErrBlankCapture) {
+		t.Errorf("checkCapture() error = %v, want decode error", err)
+	}
+}
@@ -0,0 +1,113 @@
+package playwright
+
+import (
+	"fmt"
+	"log"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/playwright-community/playwright-go"
+	"github.com/trodemaster/weatherdesktop/pkg/assets"
+)
+
+// blockCounter counts aborted requests by reason ("font", "googletagmanager.com", ...)
+type blockCounter struct {
+	mu     sync.Mutex
+	counts map[string]int
+}
+
+// installBlockList routes every request through the target's block list
+// Requests that aren't blocked fall back to the context's routes, then the network
+// Returns nil if the block list is empty (no route handler is installed)
+func (s *Scraper) installBlockList(page playwright.Page, target assets.ScrapeTarget) (*blockCounter, error) {
+	block := target.Block
+	if block == nil {
+		block = assets.DefaultBlockList()
+	}
+	if len(block.ResourceTypes) == 0 && len(block.Hosts) == 0 {
+		return nil, nil
+	}
+
+	counter := &blockCounter{counts: make(map[string]int)}
+
+	err := page.Route("**/*", func(route playwright.Route) {
+		if reason, ok := blockReason(block, route.Request()); ok {
+			counter.add(reason)
+			route.Abort("blockedbyclient")
+			return
+		}
+		// Fallback, not Continue: context routes (HAR replay) must still see the request
+		route.Fallback()
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to install request blocking: %w", err)
+	}
+
+	return counter, nil
+}
+
+// blockReason returns why a request is blocked, if it is
+func blockReason(block *assets.BlockList, req playwright.Request) (string, bool) {
+	resourceType := req.ResourceType()
+	for _, t := range block.ResourceTypes {
+		if resourceType == t {
+			return t, true
+		}
+	}
+
+	u, err := url.Parse(req.URL())
+	if err != nil {
+		return "", false
+	}
+	if pattern, ok := matchHost(block.Hosts, u.Hostname()); ok {
+		return pattern, true
+	}
+
+	return "", false
+}
+
+// matchHost returns the first pattern that host equals or is a subdomain of
+func matchHost(patterns []string, host string) (string, bool) {
+	host = strings.ToLower(host)
+	for _, pattern := range patterns {
+		pattern = strings.ToLower(pattern)
+		if host == pattern || strings.HasSuffix(host, "."+pattern) {
+			return pattern, true
+		}
+	}
+	return "", false
+}
+
+func (c *blockCounter) add(reason string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.counts[reason]++
+}
+
+// log writes the blocked request counts, most frequent first
+func (c *blockCounter) log(logger *log.Logger, name string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	total := 0
+	reasons := make([]string, 0, len(c.counts))
+	for reason, n := range c.counts {
+		total += n
+		reasons = append(reasons, reason)
+	}
+	sort.Slice(reasons, func(i, j int) bool {
+		if c.counts[reasons[i]] != c.counts[reasons[j]] {
+			return c.counts[reasons[i]] > c.counts[reasons[j]]
+		}
+		return reasons[i] < reasons[j]
+	})
+
+	parts := make([]string, 0, len(reasons))
+	for _, reason := range reasons {
+		parts = append(parts, fmt.Sprintf("%s=%d", reason, c.counts[reason]))
+	}
+
+	logger.Printf("🚫 %s: blocked %d request(s) %s", name, total, strings.Join(parts, ", "))
+}
@@ -0,0 +1,116 @@
+package playwright
+
+import (
+	"testing"
+
+	"github.com/playwright-community/playwright-go"
+	"github.com/trodemaster/weatherdesktop/pkg/assets"
+)
+
+func TestMatchHost(t *testing.T) {
+	hosts := assets.DefaultBlockList().Hosts
+
+	tests := []struct {
+		host    string
+		pattern string
+		blocked bool
+	}{
+		{"www.googletagmanager.com", "googletagmanager.com", true},
+		{"googletagmanager.com", "googletagmanager.com", true},
+		{"stats.g.doubleclick.net", "doubleclick.net", true},
+		{"DAP.DigitalGov.gov", "dap.digitalgov.gov", true},
+		{"forecast.weather.gov", "", false},
+		{"nwac.us", "", false},
+		{"notdoubleclick.net", "", false}, // Suffix must be a whole domain label
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.host, func(t *testing.T) {
+			pattern, blocked := matchHost(hosts, tt.host)
+			if blocked != tt.blocked || pattern != tt.pattern {
+				t.Errorf("matchHost(%q) = %q, %v; want %q, %v", tt.host, pattern, blocked, tt.pattern, tt.blocked)
+			}
+		})
+	}
+}
+
+// routePage captures the route handler installed on a page
+type routePage struct {
+	playwright.Page
+	handler func(playwright.Route)
+}
+
+func (p *routePage) Route(url interface{}, handler func(playwright.Route), times ...int) error {
+	p.handler = handler
+	return nil
+}
+
+// fakeRoute records how a request was handled
+type fakeRoute struct {
+	playwright.Route
+	req     fakeRequest
+	handled string
+}
+
+func (r *fakeRoute) Request() playwright.Request { return r.req }
+
+func (r *fakeRoute) Abort(errorCode ...string) error {
+	r.handled = "abort"
+	return nil
+}
+
+func (r *fakeRoute) Continue(options ...playwright.RouteContinueOptions) error {
+	r.handled = "continue"
+	return nil
+}
+
+func (r *fakeRoute) Fallback(options ...playwright.RouteFallbackOptions) error {
+	r.handled = "fallback"
+	return nil
+}
+
+type fakeRequest struct {
+	playwright.Request
+	url          string
+	resourceType string
+}
+
+func (r fakeRequest) URL() string          { return r.url }
+func (r fakeRequest) ResourceType() string { return r.resourceType }
+
+func TestInstallBlockList(t *testing.T) {
+	page := &routePage{}
+	s := NewWithOptions(Options{})
+	counter, err := s.installBlockList(page, assets.ScrapeTarget{Name: "Test"})
+	if err != nil || counter == nil || page.handler == nil {
+		t.Fatalf("installBlockList() = %v, %v; want a route handler", counter, err)
+	}
+
+	tests := []struct {
+		name         string
+		url          string
+		resourceType string
+		want         string
+	}{
+		{"blocked host", "https://www.googletagmanager.com/gtm.js", "script", "abort"},
+		{"blocked type", "https://fonts.example.com/roboto.woff2", "font", "abort"},
+		// Continue would go straight to the network, bypassing HAR replay routes on the context
+		{"allowed", "https://wsdot.com/travel/real-time/mountainpasses/stevens", "document", "fallback"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			route := &fakeRoute{req: fakeRequest{url: tt.url, resourceType: tt.resourceType}}
+			page.handler(route)
+			if route.handled != tt.want {
+				t.Errorf("request handled with %q, want %q", route.handled, tt.want)
+			}
+		})
+	}
+
+	// An empty block list installs no handler at all
+	page = &routePage{}
+	if counter, err := s.installBlockList(page, assets.ScrapeTarget{Block: &assets.BlockList{}}); counter != nil || err != nil || page.handler != nil {
+		t.Errorf("installBlockList(empty) = %v, %v; want no handler", counter, err)
+	}
+}
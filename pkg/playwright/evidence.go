@@ -0,0 +1,222 @@
+package playwright
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/playwright-community/playwright-go"
+)
+
+// DefaultEvidenceKeep is the number of failure bundles kept per target
+const DefaultEvidenceKeep = 5
+
+// evidenceTimeout bounds each page call of an evidence capture
+// A timed-out scrape may still be driving the page, so evidence must not wait on it for long
+const evidenceTimeout = 5 * time.Second
+
+// evidenceTimestamp names bundle directories so they sort chronologically
+const evidenceTimestamp = "20060102-150405"
+
+// nonSlug matches runs of characters not allowed in a bundle directory name
+var nonSlug = regexp.MustCompile(`[^a-z0-9]+`)
+
+// recorder collects page activity for a failure evidence bundle
+type recorder struct {
+	mu      sync.Mutex
+	start   time.Time
+	engine  string
+	console []string
+	errors  []string
+	failed  []string
+	timings []timing
+}
+
+// timing is a scrape phase and when it finished relative to the start of the job
+type timing struct {
+	Phase   string  `json:"phase"`
+	Elapsed float64 `json:"elapsed_seconds"`
+}
+
+// evidenceSummary is written to summary.json in each bundle
+type evidenceSummary struct {
+	Target    string    `json:"target"`
+	URL       string    `json:"url"`
+	Selector  string    `json:"selector"`
+	Extract   string    `json:"extract,omitempty"` // Extract kind (empty for screenshots)
+	Engine    string    `json:"engine"`
+	Error     string    `json:"error"`
+	StartedAt time.Time `json:"started_at"`
+	Duration  float64   `json:"duration_seconds"`
+	Timings   []timing  `json:"timings"`
+}
+
+// newRecorder attaches console, error and network listeners to page
+// In debug mode console messages and page errors are also logged as they happen
+func (s *Scraper) newRecorder(page playwright.Page, engine string) *recorder {
+	r := &recorder{start: time.Now(), engine: engine}
+
+	page.OnConsole(func(msg playwright.ConsoleMessage) {
+		if s.debug {
+			s.logger.Printf("   [Browser Console] %s: %s", msg.Type(), msg.Text())
+		}
+		r.add(&r.console, fmt.Sprintf("%s: %s", msg.Type(), msg.Text()))
+	})
+	page.OnPageError(func(err error) {
+		if s.debug {
+			s.logger.Printf("   [Page Error] %v", err)
+		}
+		r.add(&r.errors, err.Error())
+	})
+	page.OnRequestFailed(func(req playwright.Request) {
+		r.add(&r.failed, fmt.Sprintf("%s %s (%s): %v", req.Method(), req.URL(), req.ResourceType(), req.Failure()))
+	})
+	page.OnResponse(func(resp playwright.Response) {
+		if resp.Status() >= 400 {
+			r.add(&r.failed, fmt.Sprintf("%s %s (%s): HTTP %d", resp.Request().Method(), resp.URL(), resp.Request().ResourceType(), resp.Status()))
+		}
+	})
+
+	return r
+}
+
+// add appends a timestamped line to one of the recorder's logs
+func (r *recorder) add(lines *[]string, line string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	*lines = append(*lines, fmt.Sprintf("[%6.2fs] %s", time.Since(r.start).Seconds(), line))
+}
+
+// mark records that a scrape phase has finished
+func (r *recorder) mark(phase string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.timings = append(r.timings, timing{Phase: phase, Elapsed: time.Since(r.start).Seconds()})
+}
+
+// saveEvidence writes a failure bundle to <evidenceDir>/<target>/<timestamp>/
+// Individual artifacts are best effort: a page that has crashed may still yield its logs
+func (s *Scraper) saveEvidence(page playwright.Page, job scrapeJob, rec *recorder, scrapeErr error) (string, error) {
+	targetDir := filepath.Join(s.evidenceDir, evidenceSlug(job.target.Name))
+	dir := filepath.Join(targetDir, rec.start.Format(evidenceTimestamp))
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create evidence directory: %w", err)
+	}
+
+	if _, err := page.Screenshot(playwright.PageScreenshotOptions{
+		Path:     playwright.String(filepath.Join(dir, "page.png")),
+		FullPage: playwright.Bool(true),
+		Timeout:  playwright.Float(float64(evidenceTimeout.Milliseconds())),
+	}); err != nil {
+		s.logger.Printf("Warning: %s: failed to capture evidence screenshot: %v", job.target.Name, err)
+	}
+
+	if content, err := pageContent(page, evidenceTimeout); err != nil {
+		s.logger.Printf("Warning: %s: failed to capture evidence HTML: %v", job.target.Name, err)
+	} else if err := os.WriteFile(filepath.Join(dir, "page.html"), []byte(content), 0644); err != nil {
+		s.logger.Printf("Warning: %s: failed to save evidence HTML: %v", job.target.Name, err)
+	}
+
+	rec.mu.Lock()
+	logs := map[string][]string{
+		"console.log":  rec.console,
+		"errors.log":   rec.errors,
+		"requests.log": rec.failed,
+	}
+	summary := evidenceSummary{
+		Target:    job.target.Name,
+		URL:       job.target.URL,
+		Selector:  job.target.Selector,
+		Engine:    rec.engine,
+		Error:     scrapeErr.Error(),
+		StartedAt: rec.start,
+		Duration:  time.Since(rec.start).Seconds(),
+		Timings:   rec.timings,
+	}
+	rec.mu.Unlock()
+	if job.target.Extract != nil {
+		summary.Extract = string(job.target.Extract.Kind)
+	}
+
+	for name, lines := range logs {
+		data := strings.Join(lines, "\n")
+		if data != "" {
+			data += "\n"
+		}
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(data), 0644); err != nil {
+			return dir, fmt.Errorf("failed to save %s: %w", name, err)
+		}
+	}
+
+	data, err := json.MarshalIndent(summary, "", "  ")
+	if err != nil {
+		return dir, fmt.Errorf("failed to encode evidence summary: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "summary.json"), data, 0644); err != nil {
+		return dir, fmt.Errorf("failed to save evidence summary: %w", err)
+	}
+
+	pruneEvidence(s.logger, targetDir, s.evidenceKeep)
+
+	return dir, nil
+}
+
+// pageContent returns the page HTML, giving up after timeout
+// Content has no timeout of its own; an abandoned call returns once the context is closed
+func pageContent(page playwright.Page, timeout time.Duration) (string, error) {
+	type content struct {
+		html string
+		err  error
+	}
+	done := make(chan content, 1)
+	go func() {
+		html, err := page.Content()
+		done <- content{html, err}
+	}()
+
+	select {
+	case c := <-done:
+		return c.html, c.err
+	case <-time.After(timeout):
+		return "", fmt.Errorf("timed out after %s", timeout)
+	}
+}
+
+// pruneEvidence removes all but the newest keep bundles in a target's evidence directory
+func pruneEvidence(logger *log.Logger, targetDir string, keep int) {
+	entries, err := os.ReadDir(targetDir)
+	if err != nil {
+		logger.Printf("Warning: Failed to read evidence directory: %v", err)
+		return
+	}
+
+	var bundles []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			bundles = append(bundles, entry.Name())
+		}
+	}
+	if len(bundles) <= keep {
+		return
+	}
+
+	// Timestamp names sort oldest first
+	sort.Strings(bundles)
+	for _, name := range bundles[:len(bundles)-keep] {
+		if err := os.RemoveAll(filepath.Join(targetDir, name)); err != nil {
+			logger.Printf("Warning: Failed to remove old evidence %s: %v", name, err)
+		}
+	}
+}
+
+// evidenceSlug turns a target name into a directory name, e.g. "NWAC Avalanche Forecast" -> "nwac-avalanche-forecast"
+func evidenceSlug(name string) string {
+	return strings.Trim(nonSlug.ReplaceAllString(strings.ToLower(name), "-"), "-")
+}
@@ -0,0 +1,113 @@
+package playwright
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/playwright-community/playwright-go"
+	"github.com/trodemaster/weatherdesktop/pkg/assets"
+)
+
+// extractElements reads innerHTML, innerText or an attribute from the matched elements
+// Options are passed as an evaluate argument, never interpolated into the script
+const extractElements = `(els, opts) => {
+	const pick = (el) => {
+		switch (opts.kind) {
+		case "inner_html": return el.innerHTML;
+		case "inner_text": return el.innerText;
+		case "attribute": return el.getAttribute(opts.attribute);
+		}
+		return null;
+	};
+	if (!opts.all) {
+		return els.length > 0 ? pick(els[0]) : null;
+	}
+	return els.map(pick);
+}`
+
+// extract evaluates the target's extraction over the elements matching its selector
+// and returns the bytes to save
+func extract(page playwright.Page, target assets.ScrapeTarget) ([]byte, error) {
+	ex := target.Extract
+	locator := page.Locator(target.Selector)
+
+	count, err := locator.Count()
+	if err != nil {
+		return nil, fmt.Errorf("failed to count elements: %w", err)
+	}
+	if count == 0 {
+		return nil, fmt.Errorf("selector %q did not match any element", target.Selector)
+	}
+
+	switch ex.Kind {
+	case assets.ExtractInnerHTML, assets.ExtractInnerText, assets.ExtractAttribute:
+		if ex.Kind == assets.ExtractAttribute && ex.Attribute == "" {
+			return nil, fmt.Errorf("attribute extraction needs an attribute name")
+		}
+
+		result, err := locator.EvaluateAll(extractElements, map[string]interface{}{
+			"kind":      string(ex.Kind),
+			"attribute": ex.Attribute,
+			"all":       ex.All,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to evaluate: %w", err)
+		}
+		return extractedText(result)
+
+	case assets.ExtractJSON:
+		if ex.Script == "" {
+			return nil, fmt.Errorf("JSON extraction needs a script")
+		}
+
+		els := locator
+		if !ex.All {
+			els = locator.First()
+		}
+		result, err := els.EvaluateAll(ex.Script)
+		if err != nil {
+			return nil, fmt.Errorf("failed to evaluate: %w", err)
+		}
+		if result == nil {
+			return nil, fmt.Errorf("script returned null")
+		}
+
+		data, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode script result: %w", err)
+		}
+		return append(data, '\n'), nil
+	}
+
+	return nil, fmt.Errorf("unknown extract kind %q", ex.Kind)
+}
+
+// extractedText converts a string (or array of strings) evaluate result into file content
+func extractedText(result interface{}) ([]byte, error) {
+	switch v := result.(type) {
+	case string:
+		if v == "" {
+			return nil, fmt.Errorf("extracted content is empty")
+		}
+		return []byte(v), nil
+
+	case []interface{}:
+		lines := make([]string, 0, len(v))
+		for _, item := range v {
+			// Elements without the attribute yield null; skip them
+			if s, ok := item.(string); ok && s != "" {
+				lines = append(lines, s)
+			}
+		}
+		if len(lines) == 0 {
+			return nil, fmt.Errorf("extracted content is empty")
+		}
+		return []byte(strings.Join(lines, "\n") + "\n"), nil
+
+	case nil:
+		return nil, fmt.Errorf("extracted value is null (missing attribute?)")
+	}
+
+	return nil, fmt.Errorf("extracted value has unexpected type %T", result)
+}
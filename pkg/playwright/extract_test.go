@@ -0,0 +1,87 @@
+package playwright
+
+import (
+	"testing"
+
+	"github.com/trodemaster/weatherdesktop/pkg/assets"
+)
+
+// extractPage is a static forecast table with image links and data attributes
+const extractPage = `<!DOCTYPE html>
+<html><body>
+<div class="report"><b>Pass</b> open</div>
+<table id="forecast">
+<tr class="day" data-high="41"><td>Monday</td><td><img src="/img/snow.png"></td></tr>
+<tr class="day" data-high="38"><td>Tuesday</td><td><img></td></tr>
+<tr class="day" data-high="44"><td>Wednesday</td><td><img src="/img/sun.png"></td></tr>
+</table>
+</body></html>`
+
+func TestExtract(t *testing.T) {
+	_, page := newTestPage(t, extractPage)
+
+	highs := `(rows) => rows.map((row) => ({day: row.cells[0].textContent, high: Number(row.dataset.high)}))`
+
+	tests := []struct {
+		name     string
+		selector string
+		extract  assets.Extract
+		want     string
+		wantErr  bool
+	}{
+		{"inner html", ".report", assets.Extract{Kind: assets.ExtractInnerHTML}, "<b>Pass</b> open", false},
+		{"inner text", ".report", assets.Extract{Kind: assets.ExtractInnerText}, "Pass open", false},
+		{"inner text all", ".day td:first-child", assets.Extract{Kind: assets.ExtractInnerText, All: true}, "Monday\nTuesday\nWednesday\n", false},
+		{"attribute", ".day img", assets.Extract{Kind: assets.ExtractAttribute, Attribute: "src"}, "/img/snow.png", false},
+		{"attribute all skips missing", ".day img", assets.Extract{Kind: assets.ExtractAttribute, Attribute: "src", All: true}, "/img/snow.png\n/img/sun.png\n", false},
+		{"attribute without name", ".day img", assets.Extract{Kind: assets.ExtractAttribute}, "", true},
+		{"missing attribute", ".report", assets.Extract{Kind: assets.ExtractAttribute, Attribute: "href"}, "", true},
+		{"json", ".day", assets.Extract{Kind: assets.ExtractJSON, Script: highs}, "[\n  {\n    \"day\": \"Monday\",\n    \"high\": 41\n  }\n]\n", false},
+		{"json all", ".day", assets.Extract{Kind: assets.ExtractJSON, Script: `(rows) => rows.length`, All: true}, "3\n", false},
+		{"json null", ".day", assets.Extract{Kind: assets.ExtractJSON, Script: `() => null`}, "", true},
+		{"json without script", ".day", assets.Extract{Kind: assets.ExtractJSON}, "", true},
+		{"no match", "#missing", assets.Extract{Kind: assets.ExtractInnerHTML}, "", true},
+		{"unknown kind", ".report", assets.Extract{Kind: "bogus"}, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ex := tt.extract
+			got, err := extract(page, assets.ScrapeTarget{Name: "Test", Selector: tt.selector, Extract: &ex})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("extract() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if string(got) != tt.want {
+				t.Errorf("extract() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExtractedText(t *testing.T) {
+	tests := []struct {
+		name    string
+		result  interface{}
+		want    string
+		wantErr bool
+	}{
+		{"string", "<p>open</p>", "<p>open</p>", false},
+		{"empty string", "", "", true},
+		{"list", []interface{}{"a", nil, "", "b"}, "a\nb\n", false},
+		{"empty list", []interface{}{nil}, "", true},
+		{"null", nil, "", true},
+		{"number", 3.0, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := extractedText(tt.result)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("extractedText() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if string(got) != tt.want {
+				t.Errorf("extractedText() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,172 @@
+package playwright
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+
+	"github.com/playwright-community/playwright-go"
+	"github.com/trodemaster/weatherdesktop/pkg/assets"
+)
+
+// HARMode selects network recording or replay for a scrape run
+type HARMode string
+
+const (
+	HAROff    HARMode = ""
+	HARRecord HARMode = "record" // Save each target's page load to <HARDir>/<target>.har
+	HARReplay HARMode = "replay" // Serve each target from its HAR; unmatched requests are aborted
+)
+
+// Golden comparison thresholds
+const (
+	goldenPixelTolerance = 16   // Per-channel difference (0-255) before a pixel counts as changed
+	goldenMaxDiffShare   = 0.01 // Share of changed pixels allowed
+)
+
+// Golden comparison errors
+var (
+	ErrGoldenMismatch = errors.New("screenshot differs from golden")
+	ErrGoldenMissing  = errors.New("no golden screenshot (replay with UpdateGolden to create it)")
+)
+
+// harPath returns the HAR archive for a target
+func (s *Scraper) harPath(target assets.ScrapeTarget) string {
+	return filepath.Join(s.harDir, evidenceSlug(target.Name)+".har")
+}
+
+// goldenPath returns the golden screenshot for a target
+func (s *Scraper) goldenPath(target assets.ScrapeTarget) string {
+	return filepath.Join(s.harDir, evidenceSlug(target.Name)+".png")
+}
+
+// recordHAROptions adds HAR recording to the context options in record mode
+// The HAR is written when the context is closed
+func (s *Scraper) recordHAROptions(opts *playwright.BrowserNewContextOptions, target assets.ScrapeTarget) error {
+	if s.harMode != HARRecord {
+		return nil
+	}
+	if err := os.MkdirAll(s.harDir, 0755); err != nil {
+		return fmt.Errorf("failed to create HAR directory: %w", err)
+	}
+	opts.RecordHarPath = playwright.String(s.harPath(target))
+	opts.RecordHarContent = playwright.HarContentPolicyEmbed // Single self-contained file
+	opts.RecordHarMode = playwright.HarModeMinimal
+	return nil
+}
+
+// replayHAR serves the target's requests from its HAR in replay mode
+func (s *Scraper) replayHAR(bctx playwright.BrowserContext, target assets.ScrapeTarget) error {
+	if s.harMode != HARReplay {
+		return nil
+	}
+	path := s.harPath(target)
+	if _, err := os.Stat(path); err != nil {
+		return fmt.Errorf("no HAR recording for target: %w", err)
+	}
+	if err := bctx.RouteFromHAR(path, playwright.BrowserContextRouteFromHAROptions{
+		NotFound: playwright.HarNotFoundAbort, // Never fall through to the network
+	}); err != nil {
+		return fmt.Errorf("failed to replay HAR: %w", err)
+	}
+	return nil
+}
+
+// compareGolden checks a replayed screenshot against the target's golden image
+// UpdateGolden writes the screenshot as the new golden; on mismatch (or a missing golden)
+// the screenshot is saved next to the golden as <target>.actual.png
+func (s *Scraper) compareGolden(target assets.ScrapeTarget, screenshot []byte) error {
+	path := s.goldenPath(target)
+
+	if s.updateGolden {
+		if err := os.WriteFile(path, screenshot, 0644); err != nil {
+			return fmt.Errorf("failed to save golden: %w", err)
+		}
+		s.logger.Printf("Saved golden screenshot to %s", path)
+		return nil
+	}
+
+	golden, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		actual := s.saveActual(target, screenshot)
+		return fmt.Errorf("%w: %s (see %s)", ErrGoldenMissing, path, actual)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read golden: %w", err)
+	}
+
+	share, err := diffPNG(golden, screenshot)
+	if err != nil {
+		return err
+	}
+	if share > goldenMaxDiffShare {
+		actual := s.saveActual(target, screenshot)
+		return fmt.Errorf("%w: %.2f%% of pixels changed (see %s)", ErrGoldenMismatch, share*100, actual)
+	}
+
+	if s.debug {
+		s.logger.Printf("✓ Matches golden (%.2f%% of pixels changed)", share*100)
+	}
+	return nil
+}
+
+// saveActual saves a screenshot that failed its golden check as <target>.actual.png and returns its path
+func (s *Scraper) saveActual(target assets.ScrapeTarget, screenshot []byte) string {
+	actual := filepath.Join(s.harDir, evidenceSlug(target.Name)+".actual.png")
+	if err := os.WriteFile(actual, screenshot, 0644); err != nil {
+		s.logger.Printf("Warning: Failed to save %s: %v", actual, err)
+	}
+	return actual
+}
+
+// diffPNG decodes two PNGs and returns the share of pixels that differ
+// Images of different sizes differ completely
+func diffPNG(a, b []byte) (float64, error) {
+	imgA, err := png.Decode(bytes.NewReader(a))
+	if err != nil {
+		return 0, fmt.Errorf("failed to decode golden: %w", err)
+	}
+	imgB, err := png.Decode(bytes.NewReader(b))
+	if err != nil {
+		return 0, fmt.Errorf("failed to decode screenshot: %w", err)
+	}
+	return diffShare(imgA, imgB), nil
+}
+
+// diffShare returns the share of pixels whose channels differ by more than goldenPixelTolerance
+func diffShare(a, b image.Image) float64 {
+	ba, bb := a.Bounds(), b.Bounds()
+	if ba.Size() != bb.Size() {
+		return 1
+	}
+	if ba.Empty() {
+		return 0
+	}
+
+	changed := 0
+	for y := 0; y < ba.Dy(); y++ {
+		for x := 0; x < ba.Dx(); x++ {
+			r1, g1, b1, a1 := a.At(ba.Min.X+x, ba.Min.Y+y).RGBA()
+			r2, g2, b2, a2 := b.At(bb.Min.X+x, bb.Min.Y+y).RGBA()
+			if channelDiff(r1, r2) > goldenPixelTolerance || channelDiff(g1, g2) > goldenPixelTolerance ||
+				channelDiff(b1, b2) > goldenPixelTolerance || channelDiff(a1, a2) > goldenPixelTolerance {
+				changed++
+			}
+		}
+	}
+
+	return float64(changed) / float64(ba.Dx()*ba.Dy())
+}
+
+// channelDiff returns the 8-bit difference between two 16-bit color channels
+func channelDiff(c1, c2 uint32) uint32 {
+	c1, c2 = c1>>8, c2>>8
+	if c1 > c2 {
+		return c1 - c2
+	}
+	return c2 - c1
+}
@@ -0,0 +1,239 @@
+package playwright
+
+import (
+	"encoding/json"
+	"errors"
+	"image"
+	"image/color"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"testing"
+
+	"github.com/trodemaster/weatherdesktop/pkg/assets"
+)
+
+func TestDiffShare(t *testing.T) {
+	base := image.NewRGBA(image.Rect(0, 0, 100, 100))
+	for i := range base.Pix {
+		base.Pix[i] = 255
+	}
+
+	// Anti-aliasing noise below the tolerance
+	noisy := image.NewRGBA(base.Bounds())
+	copy(noisy.Pix, base.Pix)
+	for x := 0; x < 100; x++ {
+		noisy.Set(x, 50, color.RGBA{250, 250, 250, 255})
+	}
+
+	// A new 20x20 block (4% of pixels)
+	changed := image.NewRGBA(base.Bounds())
+	copy(changed.Pix, base.Pix)
+	for y := 0; y < 20; y++ {
+		for x := 0; x < 20; x++ {
+			changed.Set(x, y, color.Black)
+		}
+	}
+
+	tests := []struct {
+		name string
+		img  image.Image
+		want float64
+	}{
+		{"identical", base, 0},
+		{"noise within tolerance", noisy, 0},
+		{"changed block", changed, 0.04},
+		{"different size", image.NewRGBA(image.Rect(0, 0, 100, 99)), 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := diffShare(base, tt.img); got != tt.want {
+				t.Errorf("diffShare() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompareGolden(t *testing.T) {
+	dir := t.TempDir()
+	target := assets.ScrapeTarget{Name: "Test Chart"}
+	white := encodePNG(t, 40, 30, func(x, y int) color.Color { return color.White })
+	black := encodePNG(t, 40, 30, func(x, y int) color.Color { return color.Black })
+
+	// A missing golden fails instead of silently becoming the golden
+	s := NewWithOptions(Options{HARMode: HARReplay, HARDir: dir})
+	if err := s.compareGolden(target, white); !errors.Is(err, ErrGoldenMissing) {
+		t.Fatalf("compareGolden(no golden) error = %v, want %v", err, ErrGoldenMissing)
+	}
+	if _, err := os.Stat(s.goldenPath(target)); !os.IsNotExist(err) {
+		t.Errorf("compareGolden(no golden) created %s", s.goldenPath(target))
+	}
+
+	update := NewWithOptions(Options{HARMode: HARReplay, HARDir: dir, UpdateGolden: true})
+	if err := update.compareGolden(target, white); err != nil {
+		t.Fatalf("compareGolden(update) error = %v", err)
+	}
+
+	if err := s.compareGolden(target, white); err != nil {
+		t.Errorf("compareGolden(same) error = %v", err)
+	}
+	if err := s.compareGolden(target, black); !errors.Is(err, ErrGoldenMismatch) {
+		t.Errorf("compareGolden(changed) error = %v, want %v", err, ErrGoldenMismatch)
+	}
+}
+
+// TestReplayGolden replays every target that has a recording in testfiles/har and
+// compares its screenshot with the golden image next to the recording
+// Record with: wd-worker scrape --har record, then copy assets/har/<target>.{har,png} to testfiles/har
+// wsdot-stevens-pass-status.har is built from testfiles/wsdot_stevens_pass.html rather than recorded
+func TestReplayGolden(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping browser replay in short mode")
+	}
+
+	_, file, _, _ := runtime.Caller(0)
+	projectRoot := filepath.Join(filepath.Dir(file), "..", "..")
+	harDir := filepath.Join(projectRoot, "testfiles", "har")
+	outDir := t.TempDir()
+
+	s := NewWithOptions(Options{HARMode: HARReplay, HARDir: harDir})
+
+	mgr := assets.NewManager(projectRoot)
+	var jobs []scrapeJob
+	for _, target := range append(mgr.GetScrapeTargets(), mgr.GetWSDOTHTMLTargets()...) {
+		if _, err := os.Stat(s.harPath(target)); err != nil {
+			continue
+		}
+		if target.Extract == nil {
+			if _, err := os.Stat(s.goldenPath(target)); err != nil {
+				t.Errorf("%s: HAR recording has no golden screenshot: %v", target.Name, err)
+				continue
+			}
+		}
+		target.OutputPath = filepath.Join(outDir, filepath.Base(target.OutputPath))
+		jobs = append(jobs, newJob(target))
+	}
+	if len(jobs) == 0 {
+		t.Skip("no HAR recordings in testfiles/har")
+	}
+
+	if err := s.Start(); err != nil {
+		t.Skipf("playwright not available: %v", err)
+	}
+	defer s.Stop()
+
+	for _, result := range s.runJobs(jobs) {
+		if result.Err != nil {
+			t.Errorf("%s: %v", result.Target.Name, result.Err)
+			continue
+		}
+		if result.Extracted {
+			if data, err := os.ReadFile(result.Target.OutputPath); err != nil || len(data) == 0 {
+				t.Errorf("%s: no extracted data (%v)", result.Target.Name, err)
+			}
+		}
+	}
+}
+
+// offlinePage loads a stylesheet, script, image and API call that aren't in the HAR,
+// plus a blocked analytics script
+const offlinePage = `<!DOCTYPE html>
+<html><head>
+<link rel="stylesheet" href="/style.css">
+<script src="/app.js"></script>
+<script src="https://www.googletagmanager.com/gtm.js"></script>
+</head><body>
+<div id="report">Stevens Pass open</div>
+<img src="/cam.jpg">
+<script>fetch("/api/status").catch(() => {})</script>
+</body></html>`
+
+// writeHAR writes a HAR archive that serves html at url and nothing else
+func writeHAR(t *testing.T, path, url, html string) {
+	t.Helper()
+	har := map[string]interface{}{
+		"log": map[string]interface{}{
+			"version": "1.2",
+			"creator": map[string]string{"name": "weatherdesktop", "version": "test"},
+			"entries": []interface{}{map[string]interface{}{
+				"startedDateTime": "2024-01-10T20:45:00.000Z",
+				"time":            1,
+				"request": map[string]interface{}{
+					"method": "GET", "url": url, "httpVersion": "HTTP/1.1",
+					"cookies": []interface{}{}, "headers": []interface{}{}, "queryString": []interface{}{},
+					"headersSize": -1, "bodySize": -1,
+				},
+				"response": map[string]interface{}{
+					"status": 200, "statusText": "OK", "httpVersion": "HTTP/1.1",
+					"cookies":     []interface{}{},
+					"headers":     []interface{}{map[string]string{"name": "content-type", "value": "text/html; charset=utf-8"}},
+					"content":     map[string]interface{}{"size": len(html), "mimeType": "text/html; charset=utf-8", "text": html},
+					"redirectURL": "", "headersSize": -1, "bodySize": len(html),
+				},
+				"cache":   map[string]interface{}{},
+				"timings": map[string]int{"send": -1, "wait": 1, "receive": -1},
+			}},
+		},
+	}
+	data, err := json.Marshal(har)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestReplay_Offline replays a page recorded from a local server and fails if
+// any request, including ones missing from the HAR, reaches that server
+func TestReplay_Offline(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping browser replay in short mode")
+	}
+
+	var mu sync.Mutex
+	var live []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		live = append(live, r.URL.Path)
+		mu.Unlock()
+		http.NotFound(w, r)
+	}))
+	defer srv.Close()
+
+	harDir := t.TempDir()
+	target := assets.ScrapeTarget{
+		Name:       "Offline Test",
+		URL:        srv.URL + "/",
+		Selector:   "#report",
+		OutputPath: filepath.Join(t.TempDir(), "report.txt"),
+		Extract:    &assets.Extract{Kind: assets.ExtractInnerText},
+	}
+
+	s := NewWithOptions(Options{HARMode: HARReplay, HARDir: harDir})
+	writeHAR(t, s.harPath(target), target.URL, offlinePage)
+
+	if err := s.Start(); err != nil {
+		t.Skipf("playwright not available: %v", err)
+	}
+	defer s.Stop()
+
+	for _, result := range s.runJobs([]scrapeJob{newJob(target)}) {
+		if result.Err != nil {
+			t.Fatalf("%s: %v", result.Target.Name, result.Err)
+		}
+	}
+	if data, err := os.ReadFile(target.OutputPath); err != nil || string(data) != "Stevens Pass open" {
+		t.Errorf("extracted %q (%v), want the replayed page text", data, err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(live) > 0 {
+		t.Errorf("replay made %d live request(s): %v", len(live), live)
+	}
+}
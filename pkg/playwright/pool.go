@@ -0,0 +1,176 @@
+package playwright
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/trodemaster/weatherdesktop/pkg/assets"
+)
+
+// scrapeJob is a single target queued for the worker pool
+type scrapeJob struct {
+	target  assets.ScrapeTarget
+	extract bool // Save extracted data instead of taking a screenshot
+}
+
+// newJob creates a job for target; targets with an Extract config are extraction jobs
+func newJob(target assets.ScrapeTarget) scrapeJob {
+	return scrapeJob{target: target, extract: target.Extract != nil}
+}
+
+// Result is the outcome of scraping one target
+type Result struct {
+	Target    assets.ScrapeTarget
+	Extracted bool // Extraction target (no screenshot or fallback image)
+	Engine    string
+	Err       error
+	Duration  time.Duration
+}
+
+// runJobs scrapes jobs over a bounded pool of browser contexts
+// Results are returned in job order regardless of completion order
+func (s *Scraper) runJobs(jobs []scrapeJob) []Result {
+	results := make([]Result, len(jobs))
+
+	workers := s.parallelism
+	if workers > len(jobs) {
+		workers = len(jobs)
+	}
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				results[i] = s.runJob(jobs[i])
+			}
+		}()
+	}
+
+	for i := range jobs {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	return results
+}
+
+// runJob scrapes one target in its own browser context, bounded by the target timeout
+func (s *Scraper) runJob(job scrapeJob) Result {
+	start := time.Now()
+	result := Result{Target: job.target, Extracted: job.extract, Engine: browserEngine(job.target)}
+
+	timeout := s.targetTimeout
+	if job.target.Timeout > 0 {
+		timeout = time.Duration(job.target.Timeout) * time.Millisecond
+	}
+
+	opts, err := contextOptions(job.target)
+	if err == nil {
+		err = s.recordHAROptions(&opts, job.target)
+	}
+	if err != nil {
+		result.Err = err
+		result.Duration = time.Since(start)
+		return result
+	}
+
+	browser, err := s.browserFor(result.Engine)
+	if err != nil {
+		result.Err = err
+		result.Duration = time.Since(start)
+		return result
+	}
+
+	// Separate contexts keep cookies, cache and page state isolated between targets
+	bctx, err := browser.NewContext(opts)
+	if err != nil {
+		result.Err = fmt.Errorf("failed to create browser context: %w", err)
+		result.Duration = time.Since(start)
+		return result
+	}
+
+	if err := s.replayHAR(bctx, job.target); err != nil {
+		bctx.Close()
+		result.Err = err
+		result.Duration = time.Since(start)
+		return result
+	}
+
+	page, err := bctx.NewPage()
+	if err != nil {
+		bctx.Close()
+		result.Err = fmt.Errorf("failed to create page: %w", err)
+		result.Duration = time.Since(start)
+		return result
+	}
+	s.pages.Add(1)
+	rec := s.newRecorder(page, result.Engine)
+
+	blocked, err := s.installBlockList(page, job.target)
+	if err != nil {
+		// Scrape without blocking rather than fail the target
+		s.logger.Printf("Warning: %s: %v", job.target.Name, err)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		if job.extract {
+			done <- s.scrapeExtract(page, job.target, rec)
+		} else {
+			done <- s.scrapeTarget(page, job.target, rec, start.Add(timeout))
+		}
+	}()
+
+	timedOut := false
+	select {
+	case result.Err = <-done:
+	case <-time.After(timeout):
+		result.Err = fmt.Errorf("timed out after %s", timeout)
+		timedOut = true
+	}
+
+	// Capture evidence while the page is still open
+	// After a timeout the scrape may still be running; evidence page calls are bounded by evidenceTimeout
+	if result.Err != nil && s.evidenceDir != "" {
+		if dir, err := s.saveEvidence(page, job, rec, result.Err); err != nil {
+			s.logger.Printf("Warning: Failed to save evidence for %s: %v", job.target.Name, err)
+		} else {
+			s.logger.Printf("Saved failure evidence to %s", dir)
+		}
+	}
+
+	bctx.Close()
+	if timedOut {
+		// Closing the context aborts the in-flight page calls; wait for the scrape
+		// to return so a late write cannot replace the fallback image
+		<-done
+	}
+
+	result.Duration = time.Since(start)
+
+	if s.debug {
+		status := "✓"
+		if result.Err != nil {
+			status = "✗"
+		}
+		s.logger.Printf("   %s %s finished in %.1fs (%s)", status, job.target.Name, result.Duration.Seconds(), result.Engine)
+		if blocked != nil {
+			blocked.log(s.logger, job.target.Name)
+		}
+	}
+
+	return result
+}
+
+// browserEngine returns the target's browser engine, defaulting to WebKit
+func browserEngine(target assets.ScrapeTarget) string {
+	if target.Browser == "" {
+		return assets.BrowserWebKit
+	}
+	return target.Browser
+}
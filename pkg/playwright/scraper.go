@@ -6,27 +6,100 @@ import (
 	"log"
 	"os"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/playwright-community/playwright-go"
 	"github.com/trodemaster/weatherdesktop/pkg/assets"
+	"github.com/trodemaster/weatherdesktop/pkg/results"
 )
 
-// Scraper handles web scraping using Playwright WebKit
+// Scraper defaults
+const (
+	DefaultParallelism   = 3                // NWAC targets dominate run time, so run them side by side
+	DefaultTargetTimeout = 90 * time.Second // Longest NWAC wait chain is ~70s
+)
+
+// Blank capture retries
+const (
+	captureAttempts   = 3
+	captureRetryDelay = 2 * time.Second // Multiplied by the attempt number
+)
+
+// Scraper handles web scraping using Playwright
+// Browser engines are launched on first use and shared by all targets in a run
 type Scraper struct {
-	pw      *playwright.Playwright
-	browser playwright.Browser
-	debug   bool
+	pw            *playwright.Playwright
+	browsers      map[string]playwright.Browser
+	browsersMu    sync.Mutex
+	debug         bool
+	logger        *log.Logger
+	parallelism   int
+	targetTimeout time.Duration
+	evidenceDir   string
+	evidenceKeep  int
+	harMode       HARMode
+	harDir        string
+	updateGolden  bool
+	pages         atomic.Int64 // Pages opened since Start
+}
+
+// Options configures a Scraper
+type Options struct {
+	Debug         bool
+	Parallelism   int           // Number of targets scraped concurrently (0 = DefaultParallelism)
+	TargetTimeout time.Duration // Overall limit per target (0 = DefaultTargetTimeout)
+	EvidenceDir   string        // Failure evidence bundles are saved here ("" = disabled)
+	EvidenceKeep  int           // Bundles kept per target (0 = DefaultEvidenceKeep)
+	HARMode       HARMode       // Record or replay each target's network traffic
+	HARDir        string        // HAR archives and golden screenshots
+	UpdateGolden  bool          // Replay: overwrite golden screenshots instead of comparing
+	Logger        *log.Logger   // Run log output (nil = standard logger)
 }
 
 // New creates a new Playwright scraper
 func New(debug bool) *Scraper {
-	return &Scraper{
-		debug: debug,
+	return NewWithOptions(Options{Debug: debug})
+}
+
+// NewWithOptions creates a new Playwright scraper with explicit options
+func NewWithOptions(opts Options) *Scraper {
+	s := &Scraper{
+		browsers: make(map[string]playwright.Browser),
 	}
+	s.Configure(opts)
+	return s
 }
 
-// Start initializes Playwright and launches WebKit
+// Configure replaces the scraper options
+// Running browsers are kept, so a long-lived scraper can change settings between runs
+func (s *Scraper) Configure(opts Options) {
+	if opts.Parallelism <= 0 {
+		opts.Parallelism = DefaultParallelism
+	}
+	if opts.TargetTimeout <= 0 {
+		opts.TargetTimeout = DefaultTargetTimeout
+	}
+	if opts.EvidenceKeep <= 0 {
+		opts.EvidenceKeep = DefaultEvidenceKeep
+	}
+	if opts.Logger == nil {
+		opts.Logger = log.Default()
+	}
+	s.debug = opts.Debug
+	s.logger = opts.Logger
+	s.parallelism = opts.Parallelism
+	s.targetTimeout = opts.TargetTimeout
+	s.evidenceDir = opts.EvidenceDir
+	s.evidenceKeep = opts.EvidenceKeep
+	s.harMode = opts.HARMode
+	s.harDir = opts.HARDir
+	s.updateGolden = opts.UpdateGolden
+}
+
+// Start initializes Playwright
+// Browsers are launched lazily by browserFor, so only engines used by a target are started
 func (s *Scraper) Start() error {
 	var err error
 	
@@ -36,61 +109,154 @@ func (s *Scraper) Start() error {
 		return fmt.Errorf("failed to start playwright: %w", err)
 	}
 	
-	// Launch WebKit browser (always headless in Docker - no X server)
-	s.browser, err = s.pw.WebKit.Launch(playwright.BrowserTypeLaunchOptions{
+	return nil
+}
+
+// browserFor returns the running browser for an engine, launching it on first use
+func (s *Scraper) browserFor(engine string) (playwright.Browser, error) {
+	s.browsersMu.Lock()
+	defer s.browsersMu.Unlock()
+	
+	if browser, ok := s.browsers[engine]; ok {
+		if browser.IsConnected() {
+			return browser, nil
+		}
+		// Browser process crashed or was killed; launch a new one
+		s.logger.Printf("Warning: %s browser disconnected, relaunching", engine)
+		delete(s.browsers, engine)
+	}
+	
+	var browserType playwright.BrowserType
+	switch engine {
+	case assets.BrowserWebKit:
+		browserType = s.pw.WebKit
+	case assets.BrowserChromium:
+		browserType = s.pw.Chromium
+	case assets.BrowserFirefox:
+		browserType = s.pw.Firefox
+	default:
+		return nil, fmt.Errorf("unknown browser engine %q", engine)
+	}
+	
+	// Always headless in Docker - no X server
+	browser, err := browserType.Launch(playwright.BrowserTypeLaunchOptions{
 		Headless: playwright.Bool(true), // Always headless in container
 	})
 	if err != nil {
-		return fmt.Errorf("failed to launch webkit: %w", err)
+		return nil, fmt.Errorf("failed to launch %s: %w", engine, err)
 	}
+	s.browsers[engine] = browser
 	
 	if s.debug {
-		log.Println("WebKit browser started (headless mode)")
+		s.logger.Printf("%s browser started (headless mode)", engine)
 	}
 	
-	return nil
+	return browser, nil
 }
 
-// Stop closes the browser and Playwright
+// Stop closes the browsers and Playwright
 func (s *Scraper) Stop() error {
-	if s.browser != nil {
-		if err := s.browser.Close(); err != nil {
-			log.Printf("Warning: Failed to close browser: %v", err)
+	s.browsersMu.Lock()
+	for engine, browser := range s.browsers {
+		if err := browser.Close(); err != nil {
+			s.logger.Printf("Warning: Failed to close %s browser: %v", engine, err)
 		}
 	}
+	s.browsers = make(map[string]playwright.Browser)
+	s.browsersMu.Unlock()
 	
 	if s.pw != nil {
 		if err := s.pw.Stop(); err != nil {
-			log.Printf("Warning: Failed to stop playwright: %v", err)
+			s.logger.Printf("Warning: Failed to stop playwright: %v", err)
 		}
+		s.pw = nil
 	}
 	
 	return nil
 }
 
-// ScrapeAll scrapes all configured targets
-func (s *Scraper) ScrapeAll(mgr *assets.Manager) error {
-	targets := mgr.GetScrapeTargets()
-	
-	for _, target := range targets {
-		if s.debug {
-			log.Printf("\n🌐 Scraping: %s", target.Name)
+// Restart stops Playwright and its browsers and starts a fresh driver
+// Long-lived scrapers call this periodically to shed browser memory growth
+func (s *Scraper) Restart() error {
+	s.Stop()
+	s.pages.Store(0)
+	return s.Start()
+}
+
+// PagesServed returns the number of pages opened since the last Start
+func (s *Scraper) PagesServed() int64 {
+	return s.pages.Load()
+}
+
+// Run scrapes every target, or only the screenshot targets matching filter plus the WSDOT passes
+func (s *Scraper) Run(mgr *assets.Manager, filter string) error {
+	s.logger.Println("Scraping sites...")
+	
+	// Scrape targets (ScrapeAll includes the WSDOT pass HTML)
+	if filter != "" {
+		if err := s.ScrapeFiltered(mgr, filter); err != nil {
+			return fmt.Errorf("filtered scrape failed: %w", err)
 		}
 		
-		if err := s.scrapeTarget(target); err != nil {
-			log.Printf("❌ Failed to scrape %s: %v", target.Name, err)
+		// Also scrape WSDOT pass HTML
+		if err := s.ScrapeWSDOTPasses(mgr); err != nil {
+			s.logger.Printf("Warning: Failed to scrape WSDOT HTML: %v", err)
+		}
+	} else {
+		if err := s.ScrapeAll(mgr); err != nil {
+			return fmt.Errorf("scrape failed: %w", err)
+		}
+	}
+	
+	s.logger.Println("Asset Collection Completed...")
+	return nil
+}
+
+// ScrapeAll scrapes all configured targets, including the WSDOT pass HTML targets
+// Targets run concurrently; results are reported in configuration order
+func (s *Scraper) ScrapeAll(mgr *assets.Manager) error {
+	var jobs []scrapeJob
+	for _, target := range mgr.GetScrapeTargets() {
+		jobs = append(jobs, newJob(target))
+	}
+	for _, target := range mgr.GetWSDOTHTMLTargets() {
+		jobs = append(jobs, newJob(target))
+	}
+	
+	start := time.Now()
+	scraped := s.runJobs(jobs)
+	
+	var failed int
+	for _, result := range scraped {
+		if result.Err != nil {
+			failed++
+			s.logger.Printf("❌ Failed to scrape %s (%.1fs): %v", result.Target.Name, result.Duration.Seconds(), result.Err)
+			if result.Extracted {
+				continue
+			}
 			// Create fallback image
-			if err := s.createFallbackImage(target.OutputPath); err != nil {
-				log.Printf("Warning: Failed to create fallback image: %v", err)
+			if err := s.createFallbackImage(result.Target.OutputPath); err != nil {
+				s.logger.Printf("Warning: Failed to create fallback image: %v", err)
 			}
 			continue
 		}
 		
-		if !s.debug {
-			log.Printf("Saved screenshot to %s", target.OutputPath)
+		if !s.debug && !result.Extracted {
+			s.logger.Printf("Saved screenshot to %s", result.Target.OutputPath)
 		}
 	}
 	
+	s.logger.Printf("Scraped %d target(s) in %.1fs (%d failed, parallelism %d)", len(scraped), time.Since(start).Seconds(), failed, s.parallelism)
+	
+	// Record each target for the render HUD
+	outcomes := make([]results.Outcome, len(scraped))
+	for i, result := range scraped {
+		outcomes[i] = results.Outcome{Name: result.Target.Name, Path: result.Target.OutputPath, Err: result.Err}
+	}
+	if err := results.RecordSources(mgr.GetRunResultsPath(), outcomes...); err != nil {
+		s.logger.Printf("Warning: %v", err)
+	}
+	
 	return nil
 }
 
@@ -111,59 +277,47 @@ func (s *Scraper) ScrapeFiltered(mgr *assets.Manager, filter string) error {
 	}
 	
 	if s.debug {
-		log.Printf("🎯 Testing specific target: %s", filter)
-		log.Printf("📋 Found %d target(s) matching '%s':", len(matched), filterLower)
+		s.logger.Printf("🎯 Testing specific target: %s", filter)
+		s.logger.Printf("📋 Found %d target(s) matching '%s':", len(matched), filterLower)
 		for _, t := range matched {
-			log.Printf("   - %s", t.Name)
+			s.logger.Printf("   - %s", t.Name)
 		}
-		log.Println()
+		s.logger.Println()
 	}
 	
+	jobs := make([]scrapeJob, 0, len(matched))
 	for _, target := range matched {
-		if s.debug {
-			log.Printf("\n🌐 Scraping: %s", target.Name)
-		}
-		
-		if err := s.scrapeTarget(target); err != nil {
-			return fmt.Errorf("failed to scrape %s: %w", target.Name, err)
+		jobs = append(jobs, newJob(target))
+	}
+	
+	// Report the first failure in configuration order
+	for _, result := range s.runJobs(jobs) {
+		if result.Err != nil {
+			return fmt.Errorf("failed to scrape %s: %w", result.Target.Name, result.Err)
 		}
 		
-		if !s.debug {
-			log.Printf("Saved screenshot to %s", target.OutputPath)
+		if !s.debug && !result.Extracted {
+			s.logger.Printf("Saved screenshot to %s", result.Target.OutputPath)
 		}
 	}
 	
 	return nil
 }
 
-// scrapeTarget scrapes a single target
-func (s *Scraper) scrapeTarget(target assets.ScrapeTarget) error {
-	if s.debug {
-		log.Printf("   URL: %s", target.URL)
-		log.Printf("   Selector: %s", target.Selector)
-	}
-	
-	// Create new page
-	page, err := s.browser.NewPage()
-	if err != nil {
-		return fmt.Errorf("failed to create page: %w", err)
-	}
-	defer page.Close()
-	
-	// Set up console logging in debug mode
+// scrapeTarget screenshots a single target on the given page
+// Retries are limited to the time left before the job's deadline
+func (s *Scraper) scrapeTarget(page playwright.Page, target assets.ScrapeTarget, rec *recorder, deadline time.Time) error {
 	if s.debug {
-		page.On("console", func(msg playwright.ConsoleMessage) {
-			log.Printf("   [Browser Console] %s: %s", msg.Type(), msg.Text())
-		})
-		page.On("pageerror", func(err error) {
-			log.Printf("   [Page Error] %v", err)
-		})
+		s.logger.Printf("\n🌐 Scraping: %s", target.Name)
+		s.logger.Printf("   URL: %s", target.URL)
+		s.logger.Printf("   Engine: %s", browserEngine(target))
+		s.logger.Printf("   Selector: %s", target.Selector)
 	}
 	
 	// Navigate to URL
 	startNav := time.Now()
 	if s.debug {
-		log.Printf("⏳ Navigating to URL...")
+		s.logger.Printf("⏳ Navigating to URL...")
 	}
 	
 	// Navigate with 'domcontentloaded' - fastest option, good for slow sites
@@ -174,92 +328,70 @@ func (s *Scraper) scrapeTarget(target assets.ScrapeTarget) error {
 		// Log page content on failure for debugging
 		if s.debug {
 			if content, contentErr := page.Content(); contentErr == nil {
-				log.Printf("   [Page Content Preview] %s", content[:min(200, len(content))])
+				s.logger.Printf("   [Page Content Preview] %s", content[:min(200, len(content))])
 			}
 		}
 		return fmt.Errorf("navigation failed: %w", err)
 	}
 	
+	rec.mark("navigation")
 	if s.debug {
-		log.Printf("✓ Navigation complete (%.2fs)", time.Since(startNav).Seconds())
+		s.logger.Printf("✓ Navigation complete (%.2fs)", time.Since(startNav).Seconds())
 	}
 	
-	// Wait for element
-	waitTime := target.WaitTime
-	if waitTime == 0 {
-		waitTime = 1000 // Default 1 second
-	}
-
-	if s.debug {
-		log.Printf("⏰ Waiting %dms for element...", waitTime)
-	}
-
-	locator := page.Locator(target.Selector)
-	if err := locator.WaitFor(playwright.LocatorWaitForOptions{
-		Timeout: playwright.Float(float64(waitTime)),
-		State:   playwright.WaitForSelectorStateVisible,
-	}); err != nil {
-		if s.debug {
-			log.Printf("⚠️  Element not found after %dms, taking screenshot anyway", waitTime)
+	// Wait, prepare and capture; blank or placeholder captures are retried with longer waits
+	var screenshot []byte
+	for attempt := 1; ; attempt++ {
+		// Run the target's wait steps
+		if err := s.runWaits(page, capWaits(scaleWaits(target, attempt), time.Until(deadline))); err != nil {
+			return err
 		}
-	} else if s.debug {
-		log.Printf("✓ Element found: %s", target.Selector)
-	}
-
-	// For NWAC sites, wait for actual content to load (charts/graphs)
-	// This is more reliable than simple time-based waits
-	if strings.Contains(target.URL, "nwac.us") {
+		rec.mark("waits")
+		
+		// Dismiss banners, hide overlays, etc.
+		s.runActions(page, target)
+		rec.mark("actions")
+		
+		locator := page.Locator(target.Selector)
+		
+		// Take screenshot of the element
 		if s.debug {
-			log.Printf("⏰ Waiting for NWAC content to fully render...")
+			s.logger.Printf("📸 Taking screenshot with 10s timeout...")
 		}
-
-		// Strategy 1: Wait for canvas or SVG elements (charts are usually rendered with these)
-		contentSelector := target.Selector + " canvas, " + target.Selector + " svg"
-		contentLocator := page.Locator(contentSelector)
-
-		if err := contentLocator.First().WaitFor(playwright.LocatorWaitForOptions{
-			Timeout: playwright.Float(20000), // 20 second timeout for chart rendering
-			State:   playwright.WaitForSelectorStateVisible,
-		}); err != nil {
-			if s.debug {
-				log.Printf("⚠️  Chart elements (canvas/svg) not found: %v", err)
-				log.Printf("⏰ Falling back to network idle wait...")
-			}
-
-			// Fallback: Wait for network idle
-			if err := page.WaitForLoadState(playwright.PageWaitForLoadStateOptions{
-				State:   playwright.LoadStateNetworkidle,
-				Timeout: playwright.Float(15000),
-			}); err != nil {
-				if s.debug {
-					log.Printf("⚠️  Network idle timeout: %v", err)
-				}
-			}
-		} else if s.debug {
-			log.Printf("✓ Chart content detected")
+		var err error
+		screenshot, err = locator.Screenshot(playwright.LocatorScreenshotOptions{
+			Timeout: playwright.Float(10000), // 10 second timeout
+		})
+		if err != nil {
+			return fmt.Errorf("screenshot failed: %w", err)
 		}
-
-		// Additional wait for any animations/transitions
-		extraWait := 1500 // 1.5 seconds
-		if s.debug {
-			log.Printf("⏰ Additional %dms wait for animations...", extraWait)
+		rec.mark("screenshot")
+		
+		err = checkCapture(screenshot, expectedPixels(target))
+		if err == nil {
+			break
 		}
-		time.Sleep(time.Duration(extraWait) * time.Millisecond)
+		if attempt == captureAttempts {
+			return err
+		}
+		retryDelay := time.Duration(attempt) * captureRetryDelay
+		if time.Until(deadline) <= retryDelay {
+			return fmt.Errorf("%w (no time left to retry)", err)
+		}
+		
+		s.logger.Printf("Warning: %s: %v, retrying with longer waits (attempt %d of %d)", target.Name, err, attempt+1, captureAttempts)
+		time.Sleep(retryDelay)
 	}
 	
-	// Take screenshot of the element
-	if s.debug {
-		log.Printf("📸 Taking screenshot with 10s timeout...")
-	}
-	screenshot, err := locator.Screenshot(playwright.LocatorScreenshotOptions{
-		Timeout: playwright.Float(10000), // 10 second timeout
-	})
-	if err != nil {
-		return fmt.Errorf("screenshot failed: %w", err)
+	// Replayed captures are deterministic, so compare them to the target's golden
+	if s.harMode == HARReplay {
+		if err := s.compareGolden(target, screenshot); err != nil {
+			return err
+		}
 	}
 	
 	if s.debug {
-		log.Printf("📸 Screenshot captured: %d bytes", len(screenshot))
+		s.logger.Printf("📸 Screenshot captured: %d bytes", len(screenshot))
 	}
 	
 	// Determine output path
@@ -278,40 +410,59 @@ func (s *Scraper) scrapeTarget(target assets.ScrapeTarget) error {
 	}
 	
 	if s.debug {
-		log.Printf("✓ Saved to: %s", outputPath)
+		s.logger.Printf("✓ Saved to: %s", outputPath)
 	}
 	
 	return nil
 }
 
-// ScrapeHTML extracts HTML from a page element
-func (s *Scraper) ScrapeHTML(target assets.ScrapeTarget) error {
-	if s.debug {
-		log.Printf("\n🌐 Scraping HTML: %s", target.Name)
-		log.Printf("   URL: %s", target.URL)
-		log.Printf("   Selector: %s", target.Selector)
+// ScrapeWSDOTPasses extracts the pass report HTML for every configured pass
+// Failures are logged and skipped so one slow pass page doesn't block the others
+func (s *Scraper) ScrapeWSDOTPasses(mgr *assets.Manager) error {
+	targets := mgr.GetWSDOTHTMLTargets()
+	
+	jobs := make([]scrapeJob, 0, len(targets))
+	for _, target := range targets {
+		jobs = append(jobs, newJob(target))
 	}
 	
-	page, err := s.browser.NewPage()
-	if err != nil {
-		return fmt.Errorf("failed to create page: %w", err)
+	var failed int
+	for _, result := range s.runJobs(jobs) {
+		if result.Err != nil {
+			s.logger.Printf("Warning: Failed to scrape %s: %v", result.Target.Name, result.Err)
+			failed++
+		}
+	}
+	
+	if failed == len(targets) {
+		return fmt.Errorf("all %d WSDOT pass pages failed", len(targets))
 	}
-	defer page.Close()
 	
-	// Set up console logging in debug mode
+	return nil
+}
+
+// ScrapeHTML extracts HTML from a page element
+// Targets without an Extract config save the first matched element's innerHTML
+func (s *Scraper) ScrapeHTML(target assets.ScrapeTarget) error {
+	if target.Extract == nil {
+		target.Extract = &assets.Extract{Kind: assets.ExtractInnerHTML}
+	}
+	return s.runJob(newJob(target)).Err
+}
+
+// scrapeExtract saves data extracted from the target's elements on the given page
+func (s *Scraper) scrapeExtract(page playwright.Page, target assets.ScrapeTarget, rec *recorder) error {
 	if s.debug {
-		page.On("console", func(msg playwright.ConsoleMessage) {
-			log.Printf("   [Browser Console] %s: %s", msg.Type(), msg.Text())
-		})
-		page.On("pageerror", func(err error) {
-			log.Printf("   [Page Error] %v", err)
-		})
+		s.logger.Printf("\n🌐 Extracting %s: %s", target.Extract.Kind, target.Name)
+		s.logger.Printf("   URL: %s", target.URL)
+		s.logger.Printf("   Engine: %s", browserEngine(target))
+		s.logger.Printf("   Selector: %s", target.Selector)
 	}
 	
 	// Navigate with timeout and wait strategy
 	startNav := time.Now()
 	if s.debug {
-		log.Printf("⏳ Navigating to URL...")
+		s.logger.Printf("⏳ Navigating to URL...")
 	}
 	
 	// Use domcontentloaded like the image scraper - faster and more reliable
@@ -322,96 +473,56 @@ func (s *Scraper) ScrapeHTML(target assets.ScrapeTarget) error {
 		// Log page content on failure for debugging
 		if s.debug {
 			if content, contentErr := page.Content(); contentErr == nil {
-				log.Printf("   [Page Content Preview] %s", content[:min(200, len(content))])
+				s.logger.Printf("   [Page Content Preview] %s", content[:min(200, len(content))])
 			}
 		}
 		return fmt.Errorf("navigation failed: %w", err)
 	}
 	
+	rec.mark("navigation")
 	if s.debug {
-		log.Printf("✓ Navigation complete (%.2fs)", time.Since(startNav).Seconds())
-	}
-	
-	// Add extra wait time for Vue.js to render (WSDOT is a Vue.js app)
-	additionalWait := 3000 // 3 seconds for Vue to hydrate
-	if s.debug {
-		log.Printf("⏰ Waiting additional %dms for Vue.js to render...", additionalWait)
-	}
-	time.Sleep(time.Duration(additionalWait) * time.Millisecond)
-	
-	// Wait for element
-	waitTime := target.WaitTime
-	if waitTime == 0 {
-		waitTime = 1000 // Default 1 second
-	}
-	
-	if s.debug {
-		log.Printf("⏰ Waiting %dms for element...", waitTime)
+		s.logger.Printf("✓ Navigation complete (%.2fs)", time.Since(startNav).Seconds())
 	}
 	
-	locator := page.Locator(target.Selector)
-	if err := locator.WaitFor(playwright.LocatorWaitForOptions{
-		Timeout: playwright.Float(float64(waitTime)),
-		State:   playwright.WaitForSelectorStateVisible,
-	}); err != nil {
-		if s.debug {
-			log.Printf("⚠️  Element not found after %dms, attempting extraction anyway: %v", waitTime, err)
-		}
-		// Don't fail immediately - try to extract anyway
+	// Run the target's wait steps (a missing element is reported by the extraction below)
+	if err := s.runWaits(page, target); err != nil {
+		return err
 	}
+	rec.mark("waits")
 	
-	if s.debug {
-		log.Printf("✓ Proceeding with HTML extraction")
-	}
+	// Page actions apply to extraction too, e.g. removing elements before reading innerText
+	s.runActions(page, target)
 	
-	// Get inner HTML using evaluate (more reliable than InnerHTML for Vue.js pages)
 	if s.debug {
-		log.Printf("📄 Extracting HTML with evaluate method...")
+		s.logger.Printf("📄 Extracting %s...", target.Extract.Kind)
 	}
 	
-	// Use Page.Evaluate to extract HTML directly from DOM
-	result, err := page.Evaluate(fmt.Sprintf(`() => {
-		const el = document.querySelector('%s');
-		if (!el) {
-			console.log('Element not found with selector: %s');
-			return null;
-		}
-		console.log('Element found, extracting HTML...');
-		return el.innerHTML;
-	}`, target.Selector, target.Selector))
-	
+	// Evaluate in the page rather than InnerHTML (more reliable for Vue.js pages)
+	data, err := extract(page, target)
 	if err != nil {
 		if s.debug {
-			log.Printf("⚠️  Evaluate failed: %v", err)
+			s.logger.Printf("⚠️  Extraction failed: %v", err)
 		}
-		return fmt.Errorf("failed to evaluate: %w", err)
-	}
-	
-	if result == nil {
-		return fmt.Errorf("selector '%s' did not match any element", target.Selector)
-	}
-	
-	html, ok := result.(string)
-	if !ok || html == "" {
-		return fmt.Errorf("extracted HTML is empty or invalid type")
+		return err
 	}
+	rec.mark("extraction")
 	
 	if s.debug {
-		log.Printf("📄 HTML extracted: %d bytes", len(html))
-		if len(html) < 500 {
-			log.Printf("📄 Content preview: %s", html[:min(len(html), 200)])
+		s.logger.Printf("📄 Extracted: %d bytes", len(data))
+		if len(data) < 500 {
+			s.logger.Printf("📄 Content preview: %s", data[:min(len(data), 200)])
 		}
 	}
 	
-	// Save HTML
-	if err := os.WriteFile(target.OutputPath, []byte(html), 0644); err != nil {
-		return fmt.Errorf("failed to save HTML: %w", err)
+	// Save extracted data
+	if err := os.WriteFile(target.OutputPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to save extracted data: %w", err)
 	}
 	
 	if s.debug {
-		log.Printf("✓ Saved to: %s", target.OutputPath)
+		s.logger.Printf("✓ Saved to: %s", target.OutputPath)
 	} else {
-		log.Printf("Saved HTML to %s", target.OutputPath)
+		s.logger.Printf("Saved %s to %s", target.Extract.Kind, target.OutputPath)
 	}
 	
 	return nil
@@ -0,0 +1,237 @@
+package playwright
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/playwright-community/playwright-go"
+	"github.com/trodemaster/weatherdesktop/pkg/assets"
+)
+
+// errPageClosed is returned by a delay interrupted by the page closing
+var errPageClosed = errors.New("page closed")
+
+// defaultWaitTimeout applies to non-selector wait steps without a Timeout
+const defaultWaitTimeout = 10000 // milliseconds
+
+// imageLoaded is true once the <img> matched by sel has finished decoding
+const imageLoaded = `(sel) => {
+	const img = document.querySelector(sel);
+	return !!img && img.complete && img.naturalWidth > 0;
+}`
+
+// runWaits runs the target's wait steps in order
+// Targets without wait steps wait for their selector to be visible (optional, WaitTime or 1s)
+func (s *Scraper) runWaits(page playwright.Page, target assets.ScrapeTarget) error {
+	steps := target.Waits
+	if len(steps) == 0 {
+		steps = []assets.WaitStep{{Kind: assets.WaitSelectorVisible, Optional: true}}
+	}
+
+	for _, step := range steps {
+		if err := s.runWaitStep(page, target, step); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// runWaitStep runs one wait step, applying its fallback and optional handling
+func (s *Scraper) runWaitStep(page playwright.Page, target assets.ScrapeTarget, step assets.WaitStep) error {
+	timeout := step.Timeout
+	if timeout == 0 {
+		timeout = defaultWaitTimeout
+		switch step.Kind {
+		case assets.WaitSelectorVisible, assets.WaitChildSelector:
+			timeout = target.WaitTime
+			if timeout == 0 {
+				timeout = 1000 // Default 1 second
+			}
+		}
+	}
+
+	start := time.Now()
+	if s.debug {
+		s.logger.Printf("⏰ Wait %s (%dms)...", describeWait(step, target), timeout)
+	}
+
+	err := s.wait(page, target, step, timeout)
+	if err == nil {
+		if s.debug {
+			s.logger.Printf("✓ %s (%.2fs)", describeWait(step, target), time.Since(start).Seconds())
+		}
+		return nil
+	}
+
+	if len(step.Fallback) > 0 {
+		if s.debug {
+			s.logger.Printf("⚠️  %s failed: %v", describeWait(step, target), err)
+			s.logger.Printf("⏰ Falling back to %d step(s)...", len(step.Fallback))
+		}
+		for _, fallback := range step.Fallback {
+			if err := s.runWaitStep(page, target, fallback); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if step.Optional {
+		if s.debug {
+			s.logger.Printf("⚠️  %s failed, continuing: %v", describeWait(step, target), err)
+		}
+		return nil
+	}
+
+	return fmt.Errorf("wait for %s failed: %w", describeWait(step, target), err)
+}
+
+// wait performs the Playwright call for a single wait step
+func (s *Scraper) wait(page playwright.Page, target assets.ScrapeTarget, step assets.WaitStep, timeout int) error {
+	selector := step.Selector
+	if selector == "" {
+		selector = target.Selector
+	}
+
+	switch step.Kind {
+	case assets.WaitSelectorVisible:
+		return page.Locator(selector).First().WaitFor(playwright.LocatorWaitForOptions{
+			Timeout: playwright.Float(float64(timeout)),
+			State:   playwright.WaitForSelectorStateVisible,
+		})
+
+	case assets.WaitChildSelector:
+		return page.Locator(target.Selector).Locator(step.Selector).First().WaitFor(playwright.LocatorWaitForOptions{
+			Timeout: playwright.Float(float64(timeout)),
+			State:   playwright.WaitForSelectorStateVisible,
+		})
+
+	case assets.WaitNetworkIdle:
+		return page.WaitForLoadState(playwright.PageWaitForLoadStateOptions{
+			State:   playwright.LoadStateNetworkidle,
+			Timeout: playwright.Float(float64(timeout)),
+		})
+
+	case assets.WaitJSPredicate:
+		_, err := page.WaitForFunction(step.Script, target.Selector, playwright.PageWaitForFunctionOptions{
+			Timeout: playwright.Float(float64(timeout)),
+		})
+		return err
+
+	case assets.WaitImageLoaded:
+		_, err := page.WaitForFunction(imageLoaded, selector, playwright.PageWaitForFunctionOptions{
+			Timeout: playwright.Float(float64(timeout)),
+		})
+		return err
+
+	case assets.WaitDelay:
+		return delay(page, time.Duration(timeout)*time.Millisecond)
+	}
+
+	return fmt.Errorf("unknown wait kind %q", step.Kind)
+}
+
+// delay waits for d, returning early if the page closes (e.g. when the job times out)
+func delay(page playwright.Page, d time.Duration) error {
+	closed := make(chan struct{}, 1)
+	onClose := func(playwright.Page) { closed <- struct{}{} }
+	page.Once("close", onClose)
+	defer page.RemoveListener("close", onClose)
+	if page.IsClosed() {
+		return errPageClosed
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-closed:
+		return errPageClosed
+	}
+}
+
+// describeWait returns a short description of a wait step for logs and errors
+func describeWait(step assets.WaitStep, target assets.ScrapeTarget) string {
+	switch step.Kind {
+	case assets.WaitSelectorVisible, assets.WaitImageLoaded:
+		selector := step.Selector
+		if selector == "" {
+			selector = target.Selector
+		}
+		return fmt.Sprintf("%s %s", step.Kind, selector)
+	case assets.WaitChildSelector:
+		return fmt.Sprintf("%s %s", step.Kind, step.Selector)
+	}
+	return string(step.Kind)
+}
+
+// scaleWaits returns a copy of target with its wait timeouts and delays multiplied by factor
+// Used to give a blank capture more time to render on retry
+func scaleWaits(target assets.ScrapeTarget, factor int) assets.ScrapeTarget {
+	if factor <= 1 {
+		return target
+	}
+
+	if target.WaitTime == 0 {
+		target.WaitTime = 1000 // Default 1 second
+	}
+	target.WaitTime *= factor
+	target.Waits = scaleSteps(target.Waits, factor)
+	return target
+}
+
+// scaleSteps multiplies explicit step timeouts by factor
+// Steps without a timeout use WaitTime or defaultWaitTimeout; the former is scaled by scaleWaits
+func scaleSteps(steps []assets.WaitStep, factor int) []assets.WaitStep {
+	if steps == nil {
+		return nil
+	}
+
+	scaled := make([]assets.WaitStep, len(steps))
+	for i, step := range steps {
+		if step.Timeout == 0 && step.Kind != assets.WaitSelectorVisible && step.Kind != assets.WaitChildSelector {
+			step.Timeout = defaultWaitTimeout
+		}
+		step.Timeout *= factor
+		step.Fallback = scaleSteps(step.Fallback, factor)
+		scaled[i] = step
+	}
+	return scaled
+}
+
+// capWaits returns a copy of target with every wait timeout and delay limited to remaining
+// Keeps scaled retries inside the job deadline
+func capWaits(target assets.ScrapeTarget, remaining time.Duration) assets.ScrapeTarget {
+	limit := max(int(remaining.Milliseconds()), 1) // 0 would mean "use the default"
+
+	if target.WaitTime == 0 {
+		target.WaitTime = 1000 // Default 1 second
+	}
+	target.WaitTime = min(target.WaitTime, limit)
+	target.Waits = capSteps(target.Waits, limit)
+	return target
+}
+
+// capSteps limits step timeouts to limit milliseconds
+// Steps without a timeout get the default they would otherwise use, then the limit
+func capSteps(steps []assets.WaitStep, limit int) []assets.WaitStep {
+	if steps == nil {
+		return nil
+	}
+
+	capped := make([]assets.WaitStep, len(steps))
+	for i, step := range steps {
+		if step.Timeout == 0 && step.Kind != assets.WaitSelectorVisible && step.Kind != assets.WaitChildSelector {
+			step.Timeout = defaultWaitTimeout
+		}
+		if step.Timeout > limit {
+			step.Timeout = limit
+		}
+		step.Fallback = capSteps(step.Fallback, limit)
+		capped[i] = step
+	}
+	return capped
+}
@@ -0,0 +1,150 @@
+package playwright
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/playwright-community/playwright-go"
+	"github.com/trodemaster/weatherdesktop/pkg/assets"
+)
+
+// waitPage has a visible, a hidden and a late element for wait step tests
+const waitPage = `<!DOCTYPE html>
+<html><body>
+<div id="ready">ready</div>
+<div id="hidden" style="display: none">hidden</div>
+<script>
+setTimeout(() => document.body.insertAdjacentHTML("beforeend", '<p id="late">late</p>'), 300);
+</script>
+</body></html>`
+
+// newTestPage serves html from a local server and opens it in a WebKit page
+// Skips the test when Playwright or its browsers are not installed
+func newTestPage(t *testing.T, html string) (*Scraper, playwright.Page) {
+	t.Helper()
+	if testing.Short() {
+		t.Skip("skipping browser test in short mode")
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		io.WriteString(w, html)
+	}))
+	t.Cleanup(srv.Close)
+
+	s := NewWithOptions(Options{})
+	if err := s.Start(); err != nil {
+		t.Skipf("playwright not available: %v", err)
+	}
+	t.Cleanup(func() { s.Stop() })
+
+	browser, err := s.browserFor(assets.BrowserWebKit)
+	if err != nil {
+		t.Skipf("webkit not available: %v", err)
+	}
+	page, err := browser.NewPage()
+	if err != nil {
+		t.Fatalf("failed to create page: %v", err)
+	}
+	if _, err := page.Goto(srv.URL); err != nil {
+		t.Fatalf("failed to load test page: %v", err)
+	}
+	return s, page
+}
+
+func TestRunWaitStep(t *testing.T) {
+	s, page := newTestPage(t, waitPage)
+	target := assets.ScrapeTarget{Name: "Test", Selector: "#ready", WaitTime: 200}
+
+	tests := []struct {
+		name    string
+		step    assets.WaitStep
+		wantErr bool
+	}{
+		{"visible", assets.WaitStep{Kind: assets.WaitSelectorVisible}, false},
+		{"hidden", assets.WaitStep{Kind: assets.WaitSelectorVisible, Selector: "#hidden"}, true},
+		{"missing optional", assets.WaitStep{Kind: assets.WaitSelectorVisible, Selector: "#missing", Optional: true}, false},
+		{"fallback", assets.WaitStep{
+			Kind: assets.WaitSelectorVisible, Selector: "#missing",
+			Fallback: []assets.WaitStep{{Kind: assets.WaitSelectorVisible, Selector: "#ready"}},
+		}, false},
+		{"failing fallback", assets.WaitStep{
+			Kind: assets.WaitSelectorVisible, Selector: "#missing",
+			Fallback: []assets.WaitStep{{Kind: assets.WaitSelectorVisible, Selector: "#hidden"}},
+		}, true},
+		{"optional fallback", assets.WaitStep{
+			Kind: assets.WaitSelectorVisible, Selector: "#missing",
+			Fallback: []assets.WaitStep{{Kind: assets.WaitSelectorVisible, Selector: "#hidden", Optional: true}},
+		}, false},
+		{"late element", assets.WaitStep{Kind: assets.WaitJSPredicate, Script: `() => !!document.querySelector("#late")`, Timeout: 3000}, false},
+		{"predicate timeout", assets.WaitStep{Kind: assets.WaitJSPredicate, Script: `() => false`, Timeout: 200}, true},
+		{"delay", assets.WaitStep{Kind: assets.WaitDelay, Timeout: 50}, false},
+		{"unknown kind", assets.WaitStep{Kind: "bogus"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := s.runWaitStep(page, target, tt.step)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("runWaitStep() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDelay_PageClosed(t *testing.T) {
+	_, page := newTestPage(t, waitPage)
+
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		page.Close()
+	}()
+
+	start := time.Now()
+	err := delay(page, 10*time.Second)
+	if !errors.Is(err, errPageClosed) {
+		t.Errorf("delay() error = %v, want %v", err, errPageClosed)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("delay() returned after %v, want it to stop when the page closes", elapsed)
+	}
+}
+
+func TestCapWaits(t *testing.T) {
+	target := assets.ScrapeTarget{
+		Waits: []assets.WaitStep{
+			{Kind: assets.WaitSelectorVisible},
+			{Kind: assets.WaitNetworkIdle},
+			{Kind: assets.WaitDelay, Timeout: 2000},
+			{Kind: assets.WaitJSPredicate, Timeout: 30000, Fallback: []assets.WaitStep{{Kind: assets.WaitDelay, Timeout: 40000}}},
+		},
+	}
+
+	// Third attempt with 15s left before the job deadline
+	capped := capWaits(scaleWaits(target, 3), 15*time.Second)
+	if capped.WaitTime != 3000 {
+		t.Errorf("WaitTime = %d, want 3000 (scaled default, under the limit)", capped.WaitTime)
+	}
+	want := []int{0, 15000, 6000, 15000}
+	for i, step := range capped.Waits {
+		if step.Timeout != want[i] {
+			t.Errorf("step %d (%s) timeout = %d, want %d", i, step.Kind, step.Timeout, want[i])
+		}
+	}
+	if got := capped.Waits[3].Fallback[0].Timeout; got != 15000 {
+		t.Errorf("fallback timeout = %d, want 15000", got)
+	}
+
+	// Out of time: every wait is as short as possible without meaning "default"
+	capped = capWaits(target, -time.Second)
+	if capped.WaitTime != 1 || capped.Waits[1].Timeout != 1 {
+		t.Errorf("expired deadline: WaitTime = %d, network idle timeout = %d, want 1 and 1", capped.WaitTime, capped.Waits[1].Timeout)
+	}
+	if target.Waits[1].Timeout != 0 {
+		t.Error("capWaits modified the original target")
+	}
+}
@@ -0,0 +1,157 @@
+package results
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+// Warning kinds recorded in run results
+const (
+	KindSchemaDrift = "schema_drift"
+)
+
+// Warning describes a non-fatal problem detected during a run
+type Warning struct {
+	Source  string    `json:"source"`
+	Kind    string    `json:"kind"`
+	Message string    `json:"message"`
+	Time    time.Time `json:"time"`
+}
+
+// Source records the latest fetch of a scraped or downloaded source
+type Source struct {
+	Name    string    `json:"name"`
+	OK      bool      `json:"ok"`
+	Error   string    `json:"error,omitempty"`
+	Time    time.Time `json:"time"`              // Latest attempt
+	Updated time.Time `json:"updated,omitempty"` // Capture time of the data from the latest successful attempt
+}
+
+// Outcome is the result of fetching one source into a file
+type Outcome struct {
+	Name string
+	Path string // Fetched file; its modification time is the data's capture time
+	Err  error
+}
+
+// RunResults holds the outcome of the most recent pipeline run.
+// Each wd-worker command runs in its own process, so results are
+// persisted to a JSON file in the assets directory and updated in place.
+type RunResults struct {
+	UpdatedAt time.Time `json:"updated_at"`
+	Warnings  []Warning `json:"warnings"`
+	Sources   []Source  `json:"sources,omitempty"`
+
+	path string
+}
+
+// Load reads run results from path, returning empty results if the file does not exist
+func Load(path string) (*RunResults, error) {
+	r := &RunResults{path: path}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return r, nil
+	}
+	if err != nil {
+		return r, fmt.Errorf("failed to read run results: %w", err)
+	}
+
+	if err := json.Unmarshal(data, r); err != nil {
+		return r, fmt.Errorf("failed to parse run results: %w", err)
+	}
+
+	return r, nil
+}
+
+// Save writes run results back to the file they were loaded from
+func (r *RunResults) Save() error {
+	r.UpdatedAt = time.Now()
+
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode run results: %w", err)
+	}
+
+	if err := os.WriteFile(r.path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write run results: %w", err)
+	}
+
+	return nil
+}
+
+// SetWarning records a warning, replacing any existing warning with the same source and kind
+func (r *RunResults) SetWarning(source, kind, message string) {
+	r.ClearWarning(source, kind)
+	r.Warnings = append(r.Warnings, Warning{
+		Source:  source,
+		Kind:    kind,
+		Message: message,
+		Time:    time.Now(),
+	})
+}
+
+// ClearWarning removes the warning with the given source and kind, if present
+func (r *RunResults) ClearWarning(source, kind string) {
+	kept := r.Warnings[:0]
+	for _, w := range r.Warnings {
+		if w.Source != source || w.Kind != kind {
+			kept = append(kept, w)
+		}
+	}
+	r.Warnings = kept
+}
+
+// SetSource records the outcome of fetching a source, replacing any earlier record
+// updated is the capture time of the fetched data; it is kept from the earlier record on failure
+func (r *RunResults) SetSource(name string, updated time.Time, err error) {
+	src := Source{Name: name, OK: err == nil, Time: time.Now(), Updated: updated}
+	if err != nil {
+		src.Error = err.Error()
+		if prev, ok := r.Source(name); ok {
+			src.Updated = prev.Updated
+		}
+	}
+
+	for i := range r.Sources {
+		if r.Sources[i].Name == name {
+			r.Sources[i] = src
+			return
+		}
+	}
+	r.Sources = append(r.Sources, src)
+}
+
+// Source returns the record for the named source, if present
+func (r *RunResults) Source(name string) (Source, bool) {
+	for _, src := range r.Sources {
+		if src.Name == name {
+			return src, true
+		}
+	}
+	return Source{}, false
+}
+
+// RecordSources saves the outcome of each fetch in the run results at path
+// Results that fail to load are replaced, so the error is returned after saving
+func RecordSources(path string, outcomes ...Outcome) error {
+	r, loadErr := Load(path)
+
+	for _, o := range outcomes {
+		var updated time.Time
+		if o.Err == nil {
+			updated = time.Now()
+			if info, err := os.Stat(o.Path); err == nil {
+				updated = info.ModTime()
+			}
+		}
+		r.SetSource(o.Name, updated, o.Err)
+	}
+
+	if err := r.Save(); err != nil {
+		return err
+	}
+	return loadErr
+}
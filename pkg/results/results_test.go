@@ -0,0 +1,60 @@
+package results
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSetSource(t *testing.T) {
+	updated := time.Date(2025, 1, 15, 15, 55, 0, 0, time.UTC)
+	r := &RunResults{}
+
+	r.SetSource("cam", updated, nil)
+	r.SetSource("cam", time.Time{}, errors.New("timeout"))
+
+	if len(r.Sources) != 1 {
+		t.Fatalf("got %d sources, want 1", len(r.Sources))
+	}
+	src := r.Sources[0]
+	if src.OK || src.Error != "timeout" {
+		t.Errorf("source = %+v, want failed with the error", src)
+	}
+	if !src.Updated.Equal(updated) {
+		t.Errorf("Updated = %v, want the last successful capture %v", src.Updated, updated)
+	}
+}
+
+func TestRecordSources(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "run_results.json")
+	cam := filepath.Join(dir, "cam.jpg")
+	if err := os.WriteFile(cam, []byte("jpeg"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	captured := time.Date(2025, 1, 15, 15, 55, 0, 0, time.UTC)
+	if err := os.Chtimes(cam, captured, captured); err != nil {
+		t.Fatal(err)
+	}
+
+	err := RecordSources(path,
+		Outcome{Name: "cam", Path: cam},
+		Outcome{Name: "chart", Path: filepath.Join(dir, "chart.png"), Err: errors.New("timed out")},
+	)
+	if err != nil {
+		t.Fatalf("RecordSources() error = %v", err)
+	}
+
+	r, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if src, ok := r.Source("cam"); !ok || !src.OK || !src.Updated.Equal(captured) {
+		t.Errorf("cam = %+v, want OK with the file's modification time", src)
+	}
+	if src, ok := r.Source("chart"); !ok || src.OK || src.Error != "timed out" {
+		t.Errorf("chart = %+v, want failed with its error", src)
+	}
+}
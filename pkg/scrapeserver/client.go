@@ -0,0 +1,100 @@
+package scrapeserver
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"time"
+)
+
+// ErrUnavailable is returned when no scraper service is listening on the socket
+// or whatever is listening doesn't answer health checks like the service
+var ErrUnavailable = errors.New("scraper service is not running")
+
+// Client sends scrape requests to a Server over its Unix socket
+type Client struct {
+	http *http.Client
+}
+
+// NewClient creates a client for the server listening on socketPath
+func NewClient(socketPath string) *Client {
+	return &Client{
+		http: &http.Client{
+			Transport: &http.Transport{
+				DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+					var d net.Dialer
+					return d.DialContext(ctx, "unix", socketPath)
+				},
+			},
+		},
+	}
+}
+
+// Health returns the service status, or ErrUnavailable if the service is not running
+func (c *Client) Health() (*Health, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://scraper/health", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+	defer resp.Body.Close()
+
+	// Anything listening that doesn't answer like the service counts as not running
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: health check returned %s", ErrUnavailable, resp.Status)
+	}
+
+	var health Health
+	if err := json.NewDecoder(resp.Body).Decode(&health); err != nil {
+		return nil, fmt.Errorf("%w: failed to decode health: %v", ErrUnavailable, err)
+	}
+	return &health, nil
+}
+
+// Scrape runs a scrape on the service, copying its log output to out as it arrives
+// A scrape that ran but failed returns the service's error message
+func (c *Client) Scrape(scrapeReq Request, out io.Writer) error {
+	// Check first so callers can fall back before anything is written to out
+	if _, err := c.Health(); err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(scrapeReq)
+	if err != nil {
+		return fmt.Errorf("failed to encode request: %w", err)
+	}
+
+	resp, err := c.http.Post("http://scraper/scrape", "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to send scrape request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("scraper service returned %s: %s", resp.Status, bytes.TrimSpace(msg))
+	}
+
+	if _, err := io.Copy(out, resp.Body); err != nil {
+		return fmt.Errorf("failed to read scrape output: %w", err)
+	}
+
+	// Trailers are only populated once the body has been read to EOF
+	if msg := resp.Trailer.Get(errorTrailer); msg != "" {
+		return errors.New(msg)
+	}
+
+	return nil
+}
@@ -0,0 +1,217 @@
+// Package scrapeserver keeps a Playwright scraper running inside the container
+// and serves scrape requests over HTTP on a Unix socket, so each run skips the
+// Playwright driver and browser startup
+package scrapeserver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/trodemaster/weatherdesktop/pkg/assets"
+	"github.com/trodemaster/weatherdesktop/pkg/playwright"
+)
+
+// Service defaults
+const (
+	DefaultSocket   = "/tmp/wd-scraper.sock"
+	DefaultMaxPages = 200 // ~20 full runs before the browsers are recycled
+)
+
+// errorTrailer carries the scrape error (empty on success) after the streamed log output
+const errorTrailer = "X-Scrape-Error"
+
+// Request is a single scrape run
+type Request struct {
+	Target        string        `json:"target,omitempty"` // Screenshot target filter (empty = all targets)
+	Debug         bool          `json:"debug"`
+	Parallelism   int           `json:"parallelism,omitempty"`
+	TargetTimeout time.Duration `json:"target_timeout,omitempty"`
+	EvidenceKeep  int           `json:"evidence_keep,omitempty"`
+}
+
+// Health is the service status returned by GET /health
+type Health struct {
+	StartedAt time.Time `json:"started_at"`
+	Runs      int       `json:"runs"`
+	Pages     int64     `json:"pages"` // Pages opened since the browsers were last (re)started
+	Restarts  int       `json:"restarts"`
+}
+
+// browserScraper is the part of playwright.Scraper the server drives
+type browserScraper interface {
+	Start() error
+	Stop() error
+	Restart() error
+	Configure(opts playwright.Options)
+	Run(mgr *assets.Manager, filter string) error
+	PagesServed() int64
+}
+
+// Server holds a warm scraper and runs one scrape request at a time
+type Server struct {
+	mgr      *assets.Manager
+	scraper  browserScraper
+	maxPages int
+
+	runMu    sync.Mutex // Serializes runs; scraper options are per run
+	stopped  bool       // A restart failed; Start is retried before the next run
+	healthMu sync.Mutex
+	health   Health
+}
+
+// New creates a scrape server
+// The browsers are restarted after maxPages pages (0 = DefaultMaxPages)
+func New(mgr *assets.Manager, maxPages int) *Server {
+	if maxPages <= 0 {
+		maxPages = DefaultMaxPages
+	}
+	return &Server{
+		mgr:      mgr,
+		scraper:  playwright.New(false),
+		maxPages: maxPages,
+	}
+}
+
+// ListenAndServe starts Playwright and serves requests on socketPath until ctx is cancelled
+func (s *Server) ListenAndServe(ctx context.Context, socketPath string) error {
+	// Remove a socket left behind by a previous (killed) server
+	if err := os.Remove(socketPath); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove stale socket: %w", err)
+	}
+
+	if err := s.scraper.Start(); err != nil {
+		return fmt.Errorf("failed to start playwright: %w", err)
+	}
+	defer s.scraper.Stop()
+	s.health.StartedAt = time.Now()
+
+	listener, err := net.Listen("unix", socketPath)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", socketPath, err)
+	}
+	defer os.Remove(socketPath)
+
+	server := &http.Server{Handler: s.Handler()}
+
+	go func() {
+		<-ctx.Done()
+		log.Println("Shutting down scraper service...")
+		// Let an in-flight run finish so its assets are complete
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+		defer cancel()
+		server.Shutdown(shutdownCtx)
+	}()
+
+	log.Printf("Scraper service listening on %s (browser restart every %d pages)", socketPath, s.maxPages)
+
+	if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("scraper service failed: %w", err)
+	}
+	return nil
+}
+
+// Handler returns the service's HTTP routes
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /scrape", s.handleScrape)
+	mux.HandleFunc("GET /health", s.handleHealth)
+	return mux
+}
+
+// handleScrape runs a scrape and streams its log output back to the client
+func (s *Server) handleScrape(w http.ResponseWriter, r *http.Request) {
+	var req Request
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, fmt.Sprintf("invalid request: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	s.runMu.Lock()
+	defer s.runMu.Unlock()
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("Trailer", errorTrailer)
+	w.WriteHeader(http.StatusOK)
+
+	// The run logs to the service's stderr and to this client
+	logger := log.New(io.MultiWriter(os.Stderr, &flushWriter{w: w}), "", log.LstdFlags)
+
+	if s.stopped {
+		logger.Println("Starting playwright after a failed restart...")
+		if err := s.scraper.Start(); err != nil {
+			w.Header().Set(errorTrailer, fmt.Sprintf("failed to start playwright: %v", err))
+			return
+		}
+		s.stopped = false
+		s.countRestart()
+	}
+
+	s.scraper.Configure(playwright.Options{
+		Debug:         req.Debug,
+		Parallelism:   req.Parallelism,
+		TargetTimeout: req.TargetTimeout,
+		EvidenceDir:   s.mgr.GetScrapeDebugDir(),
+		EvidenceKeep:  req.EvidenceKeep,
+		Logger:        logger,
+	})
+
+	err := s.scraper.Run(s.mgr, req.Target)
+	s.healthMu.Lock()
+	s.health.Runs++
+	s.healthMu.Unlock()
+
+	if pages := s.scraper.PagesServed(); pages >= int64(s.maxPages) {
+		logger.Printf("Restarting browsers after %d pages", pages)
+		if restartErr := s.scraper.Restart(); restartErr != nil {
+			// The scraper is left stopped; the next run tries to start it again
+			logger.Printf("Warning: Failed to restart playwright: %v", restartErr)
+			s.stopped = true
+		} else {
+			s.countRestart()
+		}
+	}
+
+	if err != nil {
+		w.Header().Set(errorTrailer, err.Error())
+	}
+}
+
+// countRestart records a browser restart in the service status
+func (s *Server) countRestart() {
+	s.healthMu.Lock()
+	s.health.Restarts++
+	s.healthMu.Unlock()
+}
+
+// handleHealth reports service status
+func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	s.healthMu.Lock()
+	health := s.health
+	s.healthMu.Unlock()
+	health.Pages = s.scraper.PagesServed()
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(health)
+}
+
+// flushWriter flushes each write so log lines reach the client as they happen
+type flushWriter struct {
+	w http.ResponseWriter
+}
+
+func (f *flushWriter) Write(p []byte) (int, error) {
+	n, err := f.w.Write(p)
+	if flusher, ok := f.w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+	return n, err
+}
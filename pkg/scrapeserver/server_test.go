@@ -0,0 +1,198 @@
+package scrapeserver
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/trodemaster/weatherdesktop/pkg/assets"
+	"github.com/trodemaster/weatherdesktop/pkg/playwright"
+)
+
+// fakeScraper logs and fails runs on demand instead of driving a browser
+type fakeScraper struct {
+	opts       playwright.Options
+	runErr     error
+	restartErr error
+	pages      int64
+	starts     int
+	restarts   int
+}
+
+func (f *fakeScraper) Start() error {
+	f.starts++
+	return nil
+}
+
+func (f *fakeScraper) Stop() error { return nil }
+
+func (f *fakeScraper) Restart() error {
+	f.restarts++
+	if f.restartErr != nil {
+		return f.restartErr
+	}
+	f.pages = 0
+	return nil
+}
+
+func (f *fakeScraper) Configure(opts playwright.Options) { f.opts = opts }
+
+func (f *fakeScraper) Run(mgr *assets.Manager, filter string) error {
+	f.opts.Logger.Printf("scraping %q", filter)
+	f.pages += 10
+	return f.runErr
+}
+
+func (f *fakeScraper) PagesServed() int64 { return f.pages }
+
+// startServer serves s on a Unix socket in a temp dir and returns a client for it
+func startServer(t *testing.T, s *Server) *Client {
+	t.Helper()
+	return startHandler(t, s.Handler())
+}
+
+// startHandler serves h on a Unix socket in a temp dir and returns a client for it
+func startHandler(t *testing.T, h http.Handler) *Client {
+	t.Helper()
+	socket := filepath.Join(t.TempDir(), "scraper.sock")
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Fatalf("failed to listen on %s: %v", socket, err)
+	}
+
+	srv := httptest.NewUnstartedServer(h)
+	srv.Listener.Close()
+	srv.Listener = listener
+	srv.Start()
+	t.Cleanup(srv.Close)
+
+	return NewClient(socket)
+}
+
+func newTestServer(t *testing.T, fake *fakeScraper, maxPages int) *Server {
+	return &Server{mgr: assets.NewManager(t.TempDir()), scraper: fake, maxPages: maxPages}
+}
+
+func TestHealth(t *testing.T) {
+	fake := &fakeScraper{pages: 42}
+	client := startServer(t, newTestServer(t, fake, 100))
+
+	health, err := client.Health()
+	if err != nil {
+		t.Fatalf("Health() error = %v", err)
+	}
+	if health.Pages != 42 || health.Runs != 0 || health.Restarts != 0 {
+		t.Errorf("Health() = %+v, want 42 pages and no runs or restarts", health)
+	}
+}
+
+func TestHealth_Unavailable(t *testing.T) {
+	client := NewClient(filepath.Join(t.TempDir(), "missing.sock"))
+	if _, err := client.Health(); !errors.Is(err, ErrUnavailable) {
+		t.Errorf("Health() error = %v, want %v", err, ErrUnavailable)
+	}
+}
+
+func TestHealth_NotTheService(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+	}{
+		{"garbage", func(w http.ResponseWriter, r *http.Request) { io.WriteString(w, "<html>not json</html>") }},
+		{"empty", func(w http.ResponseWriter, r *http.Request) {}},
+		{"error status", func(w http.ResponseWriter, r *http.Request) { http.Error(w, "not found", http.StatusNotFound) }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := startHandler(t, tt.handler)
+			if _, err := client.Health(); !errors.Is(err, ErrUnavailable) {
+				t.Errorf("Health() error = %v, want %v", err, ErrUnavailable)
+			}
+
+			// Scrape fails the same way so callers fall back to scraping in-process
+			var out bytes.Buffer
+			if err := client.Scrape(Request{}, &out); !errors.Is(err, ErrUnavailable) {
+				t.Errorf("Scrape() error = %v, want %v", err, ErrUnavailable)
+			}
+		})
+	}
+}
+
+func TestScrape(t *testing.T) {
+	fake := &fakeScraper{}
+	client := startServer(t, newTestServer(t, fake, 100))
+
+	var out bytes.Buffer
+	if err := client.Scrape(Request{Target: "nwac"}, &out); err != nil {
+		t.Fatalf("Scrape() error = %v", err)
+	}
+	if !strings.Contains(out.String(), `scraping "nwac"`) {
+		t.Errorf("Scrape() output = %q, want the run's log lines", out.String())
+	}
+
+	// The run error comes back in the trailer, after the log output
+	fake.runErr = errors.New("2 target(s) failed")
+	out.Reset()
+	err := client.Scrape(Request{}, &out)
+	if err == nil || err.Error() != "2 target(s) failed" {
+		t.Errorf("Scrape() error = %v, want the run error from the %s trailer", err, errorTrailer)
+	}
+	if !strings.Contains(out.String(), `scraping ""`) {
+		t.Errorf("Scrape() output = %q, want the run's log lines", out.String())
+	}
+
+	health, err := client.Health()
+	if err != nil {
+		t.Fatalf("Health() error = %v", err)
+	}
+	if health.Runs != 2 {
+		t.Errorf("Health().Runs = %d, want 2", health.Runs)
+	}
+}
+
+func TestScrape_InvalidRequest(t *testing.T) {
+	s := newTestServer(t, &fakeScraper{}, 100)
+	rec := httptest.NewRecorder()
+	s.Handler().ServeHTTP(rec, httptest.NewRequest("POST", "/scrape", strings.NewReader("{")))
+	if rec.Code != 400 {
+		t.Errorf("status = %d, want 400", rec.Code)
+	}
+}
+
+func TestScrape_Restart(t *testing.T) {
+	fake := &fakeScraper{restartErr: errors.New("driver crashed")}
+	client := startServer(t, newTestServer(t, fake, 10))
+
+	// A failed restart is reported but doesn't stop the service or fail the run
+	var out bytes.Buffer
+	if err := client.Scrape(Request{}, &out); err != nil {
+		t.Fatalf("Scrape() error = %v", err)
+	}
+	if !strings.Contains(out.String(), "Failed to restart playwright: driver crashed") {
+		t.Errorf("Scrape() output = %q, want the restart warning", out.String())
+	}
+
+	// The next run starts the scraper again first
+	fake.restartErr = nil
+	if err := client.Scrape(Request{}, &out); err != nil {
+		t.Fatalf("Scrape() error = %v", err)
+	}
+	if fake.starts != 1 || fake.restarts != 2 {
+		t.Errorf("starts = %d, restarts = %d, want 1 and 2", fake.starts, fake.restarts)
+	}
+
+	health, err := client.Health()
+	if err != nil {
+		t.Fatalf("Health() error = %v", err)
+	}
+	if health.Restarts != 2 {
+		t.Errorf("Health().Restarts = %d, want 2 (the retried start and the second restart)", health.Restarts)
+	}
+}
@@ -0,0 +1,84 @@
+// Package sun computes the sun's position and civil twilight times
+package sun
+
+import (
+	"math"
+	"time"
+)
+
+// CivilTwilightElevation is the sun elevation (degrees) at civil dawn and dusk
+const CivilTwilightElevation = -6.0
+
+// Elevation returns the sun's elevation above the horizon in degrees at t
+// for a location (latitude north, longitude east, in degrees)
+// Low-precision solar ephemeris, good to about 0.1 degree for current dates
+func Elevation(t time.Time, lat, lon float64) float64 {
+	// Days since J2000.0
+	n := float64(t.UTC().UnixNano())/float64(24*time.Hour) + 2440587.5 - 2451545.0
+
+	meanLon := 280.460 + 0.9856474*n
+	anomaly := radians(357.528 + 0.9856003*n)
+	eclipticLon := radians(meanLon + 1.915*math.Sin(anomaly) + 0.020*math.Sin(2*anomaly))
+	obliquity := radians(23.439 - 0.0000004*n)
+
+	rightAscension := math.Atan2(math.Cos(obliquity)*math.Sin(eclipticLon), math.Cos(eclipticLon))
+	declination := math.Asin(math.Sin(obliquity) * math.Sin(eclipticLon))
+
+	// Local sidereal time and hour angle
+	gmst := 18.697374558 + 24.06570982441908*n
+	hourAngle := radians(gmst*15+lon) - rightAscension
+
+	phi := radians(lat)
+	elevation := math.Asin(math.Sin(phi)*math.Sin(declination) + math.Cos(phi)*math.Cos(declination)*math.Cos(hourAngle))
+	return elevation * 180 / math.Pi
+}
+
+// IsDaylight reports whether the sun is above civil twilight at t
+func IsDaylight(t time.Time, lat, lon float64) bool {
+	return Elevation(t, lat, lon) > CivilTwilightElevation
+}
+
+// CivilTwilight returns civil dawn and dusk for the calendar day of t (in t's location)
+// ok is false if the sun does not cross civil twilight both ways that day (polar day or night)
+func CivilTwilight(t time.Time, lat, lon float64) (dawn, dusk time.Time, ok bool) {
+	start := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	end := start.AddDate(0, 0, 1)
+
+	above := func(at time.Time) bool { return IsDaylight(at, lat, lon) }
+
+	// Scan in 10 minute steps and refine each crossing by bisection
+	const step = 10 * time.Minute
+	prev := above(start)
+	for at := start.Add(step); !at.After(end); at = at.Add(step) {
+		cur := above(at)
+		if cur == prev {
+			continue
+		}
+		crossing := bisect(at.Add(-step), at, prev, above)
+		if cur {
+			dawn = crossing
+		} else {
+			dusk = crossing
+		}
+		prev = cur
+	}
+
+	return dawn, dusk, !dawn.IsZero() && !dusk.IsZero()
+}
+
+// bisect narrows [lo, hi] to the second where above changes from its value at lo
+func bisect(lo, hi time.Time, atLo bool, above func(time.Time) bool) time.Time {
+	for hi.Sub(lo) > time.Second {
+		mid := lo.Add(hi.Sub(lo) / 2)
+		if above(mid) == atLo {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	return hi.Truncate(time.Second)
+}
+
+func radians(deg float64) float64 {
+	return deg * math.Pi / 180
+}
@@ -0,0 +1,69 @@
+package sun
+
+import (
+	"testing"
+	"time"
+)
+
+// Seattle, where solstice civil twilight times are well known
+const (
+	seattleLat = 47.6062
+	seattleLon = -122.3321
+)
+
+func TestCivilTwilight(t *testing.T) {
+	pacific, err := time.LoadLocation("America/Los_Angeles")
+	if err != nil {
+		t.Skipf("no time zone data: %v", err)
+	}
+
+	tests := []struct {
+		day        time.Time
+		dawn, dusk string // Civil twilight, local time
+	}{
+		{time.Date(2024, 6, 21, 12, 0, 0, 0, pacific), "04:32", "21:49"},
+		{time.Date(2024, 12, 21, 12, 0, 0, 0, pacific), "07:17", "16:55"},
+	}
+
+	for _, tt := range tests {
+		dawn, dusk, ok := CivilTwilight(tt.day, seattleLat, seattleLon)
+		if !ok {
+			t.Errorf("%s: no civil twilight", tt.day.Format("2006-01-02"))
+			continue
+		}
+		checkClose(t, tt.day, "dawn", dawn, tt.dawn)
+		checkClose(t, tt.day, "dusk", dusk, tt.dusk)
+	}
+}
+
+// checkClose fails if got is more than 3 minutes from the HH:MM want on the same day
+func checkClose(t *testing.T, day time.Time, name string, got time.Time, want string) {
+	t.Helper()
+	clock, err := time.ParseInLocation("15:04", want, day.Location())
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantTime := time.Date(day.Year(), day.Month(), day.Day(), clock.Hour(), clock.Minute(), 0, 0, day.Location())
+	if d := got.Sub(wantTime); d > 3*time.Minute || d < -3*time.Minute {
+		t.Errorf("%s %s = %s, want about %s", day.Format("2006-01-02"), name, got.In(day.Location()).Format("15:04:05"), want)
+	}
+}
+
+func TestIsDaylight(t *testing.T) {
+	noon := time.Date(2024, 12, 21, 20, 0, 0, 0, time.UTC)    // 12:00 PST
+	midnight := time.Date(2024, 12, 22, 8, 0, 0, 0, time.UTC) // 00:00 PST
+	if !IsDaylight(noon, seattleLat, seattleLon) {
+		t.Error("IsDaylight(noon) = false")
+	}
+	if IsDaylight(midnight, seattleLat, seattleLon) {
+		t.Error("IsDaylight(midnight) = true")
+	}
+}
+
+func TestCivilTwilightPolar(t *testing.T) {
+	// Svalbard in midsummer: the sun never sets
+	day := time.Date(2024, 6, 21, 12, 0, 0, 0, time.UTC)
+	if _, _, ok := CivilTwilight(day, 78.22, 15.65); ok {
+		t.Error("CivilTwilight(Svalbard, June) ok = true, want polar day")
+	}
+}